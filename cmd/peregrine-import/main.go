@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/npmanos/4176Gameday-backend/internal/config"
+	"github.com/npmanos/4176Gameday-backend/internal/importer"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	var (
+		mappingPath = flag.String("mapping", "", "path of the JSON file mapping CSV columns to report fields")
+		realmID     = flag.Int64("realm", 0, "ID of the realm to import reports into")
+		reporterID  = flag.Int64("reporter", 0, "ID of the user to import reports as")
+		dryRun      = flag.Bool("dry-run", false, "validate the reports without storing them")
+	)
+
+	flag.Usage = func() {
+		fmt.Printf("Usage: %s -mapping [mapping path] -realm [realm ID] -reporter [user ID] [-dry-run] [config path] [CSV path]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 2 || *mappingPath == "" || *realmID == 0 || *reporterID == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for range c {
+			cancel()
+		}
+	}()
+
+	opts := importer.Options{RealmID: *realmID, ReporterID: *reporterID, DryRun: *dryRun}
+	if err := run(ctx, args[0], args[1], *mappingPath, opts); err != nil {
+		fmt.Printf("got error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, configPath, csvPath, mappingPath string, opts importer.Options) error {
+	c, err := config.Open(configPath)
+	if err != nil {
+		return fmt.Errorf("unable to open config: %w", err)
+	}
+
+	mappingFile, err := os.Open(mappingPath)
+	if err != nil {
+		return fmt.Errorf("unable to open mapping: %w", err)
+	}
+	defer mappingFile.Close()
+
+	mapping, err := importer.ParseMapping(mappingFile)
+	if err != nil {
+		return err
+	}
+
+	csvFile, err := os.Open(csvPath)
+	if err != nil {
+		return fmt.Errorf("unable to open CSV: %w", err)
+	}
+	defer csvFile.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(c.Server.LogLevel)
+
+	sto, err := store.New(ctx, c.DSN, logger)
+	if err != nil {
+		return fmt.Errorf("opening postgres server: %w", err)
+	}
+	defer sto.Close()
+
+	result, err := importer.Import(ctx, sto, csvFile, mapping, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Errors) != 0 {
+		for _, rowErr := range result.Errors {
+			fmt.Printf("row %d: %s\n", rowErr.Row, rowErr.Error)
+		}
+		return fmt.Errorf("%d invalid rows, no reports imported", len(result.Errors))
+	}
+
+	if result.DryRun {
+		fmt.Printf("dry run: %d reports are valid, none imported\n", result.Imported)
+	} else {
+		fmt.Printf("imported %d reports\n", result.Imported)
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"syscall"
 
 	"github.com/npmanos/4176Gameday-backend/internal/config"
+	"github.com/npmanos/4176Gameday-backend/internal/notify"
 	"github.com/npmanos/4176Gameday-backend/internal/refresh"
 	"github.com/npmanos/4176Gameday-backend/internal/server"
 	"github.com/npmanos/4176Gameday-backend/internal/store"
@@ -70,19 +71,27 @@ func run(ctx context.Context, configPath string) error {
 	defer sto.Close()
 	logger.Info("connected to postgres")
 
+	broker := notify.NewBroker()
+
 	// The cool, refreshing taste of Pepsi.
 	refresher := &refresh.Service{
-		TBA:    tba,
-		Store:  sto,
-		Logger: logger,
-		Year:   c.Year,
+		TBA:               tba,
+		Store:             sto,
+		Broker:            broker,
+		Logger:            logger,
+		Year:              c.Year,
+		Webhooks:          c.TBA.WebhookSecret != "",
+		ActiveEventBuffer: c.TBA.ActiveEventBuffer.Duration,
 	}
 
 	s := &server.Server{
-		TBA:    tba,
-		Store:  sto,
-		Logger: logger,
-		Server: c.Server,
+		TBA:              tba,
+		Store:            sto,
+		Broker:           broker,
+		Logger:           logger,
+		Refresher:        refresher,
+		Server:           c.Server,
+		TBAWebhookSecret: c.TBA.WebhookSecret,
 	}
 
 	updateCtx, updateCancel := context.WithCancel(ctx)
@@ -0,0 +1,216 @@
+// Package analysis calculates team ratings from the match results published by TBA, as
+// opposed to package summary which summarizes scouting reports.
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+)
+
+// ErrNotEnoughMatches is returned when there aren't enough scored matches to solve for
+// every team's rating (e.g. early in qualifications).
+var ErrNotEnoughMatches = errors.New("not enough scored matches to calculate ratings")
+
+type allianceResult struct {
+	teams         []string
+	score         float64
+	opponentScore float64
+	breakdown     store.ScoreBreakdown
+}
+
+// EventOPRs calculates each team's OPR, DPR, and CCWM at an event by solving the least
+// squares system of alliance scores over all scored qualification matches that have not
+// been deleted from TBA. A component OPR is calculated for every score breakdown key that
+// is numeric for every alliance.
+func EventOPRs(eventKey string, matches []store.Match) ([]store.TeamOPR, error) {
+	var results []allianceResult
+	for _, match := range matches {
+		if match.TBADeleted || match.RedScore == nil || match.BlueScore == nil || !strings.HasPrefix(match.Key, "qm") {
+			continue
+		}
+
+		results = append(results,
+			allianceResult{
+				teams:         match.RedAlliance,
+				score:         float64(*match.RedScore),
+				opponentScore: float64(*match.BlueScore),
+				breakdown:     match.RedScoreBreakdown,
+			},
+			allianceResult{
+				teams:         match.BlueAlliance,
+				score:         float64(*match.BlueScore),
+				opponentScore: float64(*match.RedScore),
+				breakdown:     match.BlueScoreBreakdown,
+			},
+		)
+	}
+
+	teamIndices := make(map[string]int)
+	var teams []string
+	for _, result := range results {
+		for _, team := range result.teams {
+			if _, ok := teamIndices[team]; !ok {
+				teamIndices[team] = 0
+				teams = append(teams, team)
+			}
+		}
+	}
+
+	if len(teams) == 0 || len(results) < len(teams) {
+		return nil, ErrNotEnoughMatches
+	}
+
+	sort.Strings(teams)
+	for i, team := range teams {
+		teamIndices[team] = i
+	}
+
+	// normal equations: (AᵀA)x = Aᵀb where each row of A is an alliance with a
+	// 1 for each team on it
+	normal := newMatrix(len(teams))
+	for _, result := range results {
+		for _, a := range result.teams {
+			for _, b := range result.teams {
+				normal[teamIndices[a]][teamIndices[b]]++
+			}
+		}
+	}
+
+	factor, err := cholesky(normal)
+	if err != nil {
+		return nil, ErrNotEnoughMatches
+	}
+
+	solve := func(value func(allianceResult) float64) []float64 {
+		rhs := make([]float64, len(teams))
+		for _, result := range results {
+			v := value(result)
+			for _, team := range result.teams {
+				rhs[teamIndices[team]] += v
+			}
+		}
+
+		return factor.solve(rhs)
+	}
+
+	oprs := solve(func(r allianceResult) float64 { return r.score })
+	dprs := solve(func(r allianceResult) float64 { return r.opponentScore })
+
+	components := make(map[string][]float64)
+	for _, key := range numericBreakdownKeys(results) {
+		key := key
+		components[key] = solve(func(r allianceResult) float64 { return r.breakdown[key].(float64) })
+	}
+
+	teamOPRs := make([]store.TeamOPR, 0, len(teams))
+	for i, team := range teams {
+		teamOPR := store.TeamOPR{
+			EventKey:   eventKey,
+			TeamKey:    team,
+			OPR:        oprs[i],
+			DPR:        dprs[i],
+			CCWM:       oprs[i] - dprs[i],
+			Components: make(store.OPRComponents),
+		}
+
+		for key, values := range components {
+			teamOPR.Components[key] = values[i]
+		}
+
+		teamOPRs = append(teamOPRs, teamOPR)
+	}
+
+	return teamOPRs, nil
+}
+
+// numericBreakdownKeys returns the score breakdown keys that hold a number for every
+// alliance result.
+func numericBreakdownKeys(results []allianceResult) []string {
+	var keys []string
+
+	for key := range results[0].breakdown {
+		numeric := true
+		for _, result := range results {
+			if _, ok := result.breakdown[key].(float64); !ok {
+				numeric = false
+				break
+			}
+		}
+
+		if numeric {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+type matrix [][]float64
+
+func newMatrix(n int) matrix {
+	m := make(matrix, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+	}
+	return m
+}
+
+// epsilon is the smallest pivot considered nonzero when factoring.
+const epsilon = 1e-9
+
+// cholesky factors a symmetric positive definite matrix into L, where LLᵀ equals the
+// given matrix.
+func cholesky(m matrix) (matrix, error) {
+	l := newMatrix(len(m))
+
+	for i := range m {
+		for j := 0; j <= i; j++ {
+			sum := m[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+
+			if i == j {
+				if sum <= epsilon {
+					return nil, fmt.Errorf("matrix is not positive definite at row %d", i)
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// solve solves LLᵀx = b for x using forward then back substitution.
+func (l matrix) solve(b []float64) []float64 {
+	n := len(l)
+
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := b[i]
+		for k := 0; k < i; k++ {
+			sum -= l[i][k] * y[k]
+		}
+		y[i] = sum / l[i][i]
+	}
+
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := y[i]
+		for k := i + 1; k < n; k++ {
+			sum -= l[k][i] * x[k]
+		}
+		x[i] = sum / l[i][i]
+	}
+
+	return x
+}
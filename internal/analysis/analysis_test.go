@@ -0,0 +1,109 @@
+package analysis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func newScore(score int) *int {
+	return &score
+}
+
+func TestEventOPRs(t *testing.T) {
+	// every team plays every other team with and against it, so with exact alliance scores
+	// each team's DPR is the other teams' combined OPR less its partner's, which is 50
+	// less its own OPR
+	oprs := map[string]float64{"frc1": 10, "frc2": 20, "frc3": 30, "frc4": 40}
+
+	match := func(key string, red, blue []string) store.Match {
+		var redScore, blueScore, redHatches, blueHatches float64
+		for _, team := range red {
+			redScore += oprs[team]
+			redHatches += oprs[team] / 10
+		}
+		for _, team := range blue {
+			blueScore += oprs[team]
+			blueHatches += oprs[team] / 10
+		}
+
+		return store.Match{
+			Key:                key,
+			RedAlliance:        red,
+			BlueAlliance:       blue,
+			RedScore:           newScore(int(redScore)),
+			BlueScore:          newScore(int(blueScore)),
+			RedScoreBreakdown:  store.ScoreBreakdown{"hatches": redHatches, "rung": "None"},
+			BlueScoreBreakdown: store.ScoreBreakdown{"hatches": blueHatches, "rung": "Low"},
+		}
+	}
+
+	fullSchedule := []store.Match{
+		match("qm1", []string{"frc1", "frc2"}, []string{"frc3", "frc4"}),
+		match("qm2", []string{"frc1", "frc3"}, []string{"frc2", "frc4"}),
+		match("qm3", []string{"frc1", "frc4"}, []string{"frc2", "frc3"}),
+	}
+
+	testCases := []struct {
+		name      string
+		matches   []store.Match
+		expectErr bool
+		expected  []store.TeamOPR
+	}{
+		{
+			name:      "no matches",
+			expectErr: true,
+		},
+		{
+			name:      "teams always on the same alliance",
+			matches:   fullSchedule[:1],
+			expectErr: true,
+		},
+		{
+			name: "unscored, deleted, and playoff matches are ignored",
+			matches: append([]store.Match{
+				{Key: "qm4", RedAlliance: []string{"frc1", "frc5"}, BlueAlliance: []string{"frc2", "frc6"}},
+				func() store.Match {
+					m := match("qm5", []string{"frc1", "frc2"}, []string{"frc3", "frc4"})
+					m.TBADeleted = true
+					*m.RedScore = 1000
+					return m
+				}(),
+				func() store.Match {
+					m := match("qf1m1", []string{"frc1", "frc2"}, []string{"frc3", "frc4"})
+					*m.RedScore = 1000
+					return m
+				}(),
+			}, fullSchedule...),
+			expected: []store.TeamOPR{
+				{EventKey: "2019abca", TeamKey: "frc1", OPR: 10, DPR: 40, CCWM: -30, Components: store.OPRComponents{"hatches": 1}},
+				{EventKey: "2019abca", TeamKey: "frc2", OPR: 20, DPR: 30, CCWM: -10, Components: store.OPRComponents{"hatches": 2}},
+				{EventKey: "2019abca", TeamKey: "frc3", OPR: 30, DPR: 20, CCWM: 10, Components: store.OPRComponents{"hatches": 3}},
+				{EventKey: "2019abca", TeamKey: "frc4", OPR: 40, DPR: 10, CCWM: 30, Components: store.OPRComponents{"hatches": 4}},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			teamOPRs, err := EventOPRs("2019abca", tt.matches)
+			if tt.expectErr {
+				if !errors.Is(err, ErrNotEnoughMatches) {
+					t.Errorf("expected not enough matches error but got: %v", err)
+				}
+				return
+			} else if err != nil {
+				t.Errorf("did not expect error but got: %v", err)
+				return
+			}
+
+			approx := cmpopts.EquateApprox(0, 1e-9)
+			if !cmp.Equal(teamOPRs, tt.expected, approx) {
+				t.Errorf("expected oprs to match, but got diff: %s", cmp.Diff(tt.expected, teamOPRs, approx))
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package assignment assigns a realm's scouts to the teams in each qualification match
+// of an event.
+package assignment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+)
+
+// qualMatches returns the qualification matches that haven't been deleted from TBA in
+// the order they're played.
+func qualMatches(matches []store.Match) []store.Match {
+	type numberedMatch struct {
+		number int
+		match  store.Match
+	}
+
+	var numbered []numberedMatch
+	for _, match := range matches {
+		if match.TBADeleted || !strings.HasPrefix(match.Key, "qm") {
+			continue
+		}
+
+		number, err := strconv.Atoi(strings.TrimPrefix(match.Key, "qm"))
+		if err != nil {
+			continue
+		}
+
+		numbered = append(numbered, numberedMatch{number: number, match: match})
+	}
+
+	sort.Slice(numbered, func(i, j int) bool { return numbered[i].number < numbered[j].number })
+
+	quals := make([]store.Match, 0, len(numbered))
+	for _, m := range numbered {
+		quals = append(quals, m.match)
+	}
+
+	return quals
+}
+
+// played returns whether a match has been played, so its assignments can't change.
+func played(match store.Match) bool {
+	return match.ActualTime != nil || match.RedScore != nil || match.BlueScore != nil
+}
+
+func matchTeams(match store.Match) []string {
+	return append(append([]string{}, match.RedAlliance...), match.BlueAlliance...)
+}
+
+// Fingerprint identifies a qualification schedule by its matches and the teams in each,
+// so that assignments can be remade when the schedule changes.
+func Fingerprint(matches []store.Match) string {
+	h := sha256.New()
+	for _, match := range qualMatches(matches) {
+		fmt.Fprintf(h, "%s:%s\n", match.Key, strings.Join(matchTeams(match), ","))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type scout struct {
+	id          int64
+	assigned    int
+	consecutive int
+	lastMatch   int
+	teams       map[string]int
+}
+
+// Schedule assigns scouts to the teams in every qualification match. Scouts with the
+// fewest assignments, then the longest rest, are picked first, and each picked scout
+// watches the team in the match they have watched the least. No scout is assigned more
+// than opts.MaxConsecutive matches in a row, and nobody is assigned during the realm
+// team's matches or the opts.TeamBreak matches on either side of them. Slots are left
+// unassigned when there aren't enough scouts available. Played matches keep their
+// assignments from existing, which count towards each scout's assignments when picking
+// scouts for the unplayed matches.
+func Schedule(matches []store.Match, scoutIDs []int64, opts store.ScoutScheduleOptions, existing []store.ScoutAssignment) []store.ScoutAssignment {
+	quals := qualMatches(matches)
+
+	breaks := make(map[int]bool)
+	if opts.TeamKey != "" {
+		for i, match := range quals {
+			for _, team := range matchTeams(match) {
+				if team != opts.TeamKey {
+					continue
+				}
+
+				for j := i - opts.TeamBreak; j <= i+opts.TeamBreak; j++ {
+					breaks[j] = true
+				}
+			}
+		}
+	}
+
+	scouts := make([]*scout, 0, len(scoutIDs))
+	scoutsByID := make(map[int64]*scout, len(scoutIDs))
+	for _, id := range scoutIDs {
+		s := &scout{id: id, lastMatch: -1, teams: make(map[string]int)}
+		scouts = append(scouts, s)
+		scoutsByID[id] = s
+	}
+
+	frozen := make(map[string][]store.ScoutAssignment)
+	for _, a := range existing {
+		frozen[a.MatchKey] = append(frozen[a.MatchKey], a)
+	}
+
+	assignments := make([]store.ScoutAssignment, 0)
+	for i, match := range quals {
+		if played(match) {
+			picked := make(map[*scout]bool)
+			for _, a := range frozen[match.Key] {
+				assignments = append(assignments, a)
+
+				if s, ok := scoutsByID[a.UserID]; ok {
+					s.assigned++
+					s.consecutive++
+					s.lastMatch = i
+					s.teams[a.TeamKey]++
+					picked[s] = true
+				}
+			}
+
+			for _, s := range scouts {
+				if !picked[s] {
+					s.consecutive = 0
+				}
+			}
+			continue
+		}
+
+		if breaks[i] {
+			for _, s := range scouts {
+				s.consecutive = 0
+			}
+			continue
+		}
+
+		available := make([]*scout, 0, len(scouts))
+		for _, s := range scouts {
+			if opts.MaxConsecutive == 0 || s.consecutive < opts.MaxConsecutive {
+				available = append(available, s)
+			}
+		}
+
+		sort.SliceStable(available, func(a, b int) bool {
+			if available[a].assigned != available[b].assigned {
+				return available[a].assigned < available[b].assigned
+			}
+			if available[a].lastMatch != available[b].lastMatch {
+				return available[a].lastMatch < available[b].lastMatch
+			}
+			return available[a].id < available[b].id
+		})
+
+		teams := matchTeams(match)
+		if len(available) > len(teams) {
+			available = available[:len(teams)]
+		}
+
+		picked := make(map[*scout]bool)
+		for _, s := range available {
+			team := -1
+			for j, t := range teams {
+				if t != "" && (team == -1 || s.teams[t] < s.teams[teams[team]]) {
+					team = j
+				}
+			}
+
+			assignments = append(assignments, store.ScoutAssignment{
+				EventKey: match.EventKey,
+				MatchKey: match.Key,
+				TeamKey:  teams[team],
+				UserID:   s.id,
+			})
+
+			s.assigned++
+			s.consecutive++
+			s.lastMatch = i
+			s.teams[teams[team]]++
+			picked[s] = true
+			teams[team] = ""
+		}
+
+		for _, s := range scouts {
+			if !picked[s] {
+				s.consecutive = 0
+			}
+		}
+	}
+
+	return assignments
+}
+
+// Scouts returns the IDs of the verified users, who are the only users that can be
+// assigned to scout.
+func Scouts(users []store.User) []int64 {
+	scouts := make([]int64, 0, len(users))
+	for _, user := range users {
+		if user.Roles.IsVerified {
+			scouts = append(scouts, user.ID)
+		}
+	}
+
+	return scouts
+}
@@ -0,0 +1,144 @@
+package assignment
+
+import (
+	"testing"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/google/go-cmp/cmp"
+)
+
+func match(key, red, blue string) store.Match {
+	return store.Match{
+		Key:          key,
+		EventKey:     "2019abca",
+		RedAlliance:  []string{red},
+		BlueAlliance: []string{blue},
+	}
+}
+
+func assign(matchKey, teamKey string, userID int64) store.ScoutAssignment {
+	return store.ScoutAssignment{
+		EventKey: "2019abca",
+		MatchKey: matchKey,
+		TeamKey:  teamKey,
+		UserID:   userID,
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	deleted := match("qm3", "frc1", "frc2")
+	deleted.TBADeleted = true
+
+	score := 10
+	played := match("qm1", "frc1", "frc2")
+	played.RedScore = &score
+
+	testCases := []struct {
+		name     string
+		matches  []store.Match
+		scouts   []int64
+		opts     store.ScoutScheduleOptions
+		existing []store.ScoutAssignment
+		expected []store.ScoutAssignment
+	}{
+		{
+			name:     "no scouts",
+			matches:  []store.Match{match("qm1", "frc1", "frc2")},
+			expected: []store.ScoutAssignment{},
+		},
+		{
+			name: "rotates teams in match order",
+			matches: []store.Match{
+				match("qm10", "frc1", "frc2"),
+				match("qf1m1", "frc1", "frc2"),
+				deleted,
+				match("qm2", "frc1", "frc2"),
+			},
+			scouts: []int64{2, 1},
+			expected: []store.ScoutAssignment{
+				assign("qm2", "frc1", 1),
+				assign("qm2", "frc2", 2),
+				assign("qm10", "frc2", 1),
+				assign("qm10", "frc1", 2),
+			},
+		},
+		{
+			name: "max consecutive",
+			matches: []store.Match{
+				match("qm1", "frc1", "frc2"),
+				match("qm2", "frc1", "frc2"),
+				match("qm3", "frc1", "frc2"),
+			},
+			scouts: []int64{1, 2, 3},
+			opts:   store.ScoutScheduleOptions{MaxConsecutive: 1},
+			expected: []store.ScoutAssignment{
+				assign("qm1", "frc1", 1),
+				assign("qm1", "frc2", 2),
+				assign("qm2", "frc1", 3),
+				assign("qm3", "frc2", 1),
+				assign("qm3", "frc1", 2),
+			},
+		},
+		{
+			name: "team break",
+			matches: []store.Match{
+				match("qm1", "frc1", "frc2"),
+				match("qm2", "frc3", "frc4"),
+				match("qm3", "frc5", "frc177"),
+				match("qm4", "frc1", "frc3"),
+				match("qm5", "frc2", "frc4"),
+			},
+			scouts: []int64{1, 2},
+			opts:   store.ScoutScheduleOptions{TeamKey: "frc177", TeamBreak: 1},
+			expected: []store.ScoutAssignment{
+				assign("qm1", "frc1", 1),
+				assign("qm1", "frc2", 2),
+				assign("qm5", "frc2", 1),
+				assign("qm5", "frc4", 2),
+			},
+		},
+		{
+			name: "keeps played match assignments",
+			matches: []store.Match{
+				played,
+				match("qm2", "frc3", "frc4"),
+			},
+			scouts: []int64{1, 2},
+			existing: []store.ScoutAssignment{
+				assign("qm1", "frc2", 1),
+				assign("qm1", "frc1", 3),
+				assign("qm2", "frc3", 3),
+			},
+			expected: []store.ScoutAssignment{
+				assign("qm1", "frc2", 1),
+				assign("qm1", "frc1", 3),
+				assign("qm2", "frc3", 2),
+				assign("qm2", "frc4", 1),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assignments := Schedule(tt.matches, tt.scouts, tt.opts, tt.existing)
+
+			if !cmp.Equal(tt.expected, assignments) {
+				t.Errorf("expected assignments to equal expected, but got diff: %s", cmp.Diff(tt.expected, assignments))
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	schedule := []store.Match{match("qm1", "frc1", "frc2"), match("qm2", "frc3", "frc4")}
+	reordered := []store.Match{match("qf1m1", "frc1", "frc3"), schedule[1], schedule[0]}
+	changed := []store.Match{match("qm1", "frc1", "frc2"), match("qm2", "frc3", "frc5")}
+
+	if Fingerprint(schedule) != Fingerprint(reordered) {
+		t.Errorf("expected fingerprint to ignore match order and playoff matches")
+	}
+
+	if Fingerprint(schedule) == Fingerprint(changed) {
+		t.Errorf("expected fingerprint to change when a match's teams change")
+	}
+}
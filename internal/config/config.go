@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/go-playground/validator.v9"
@@ -23,12 +24,39 @@ type Config struct {
 	Server Server `json:"server" validate:"dive"`
 	Year   int    `json:"year" validate:"required"`
 	TBA    struct {
-		URL    string `validate:"required"`
-		APIKey string `validate:"required"`
+		URL           string `validate:"required"`
+		APIKey        string `validate:"required"`
+		WebhookSecret string
+		// ActiveEventBuffer is how long before the first match and after the last match
+		// of each day an event is refreshed as an active event. Active events are also
+		// polled frequently from ActiveEventBuffer, or at least 10 minutes, before each
+		// match.
+		ActiveEventBuffer Duration
 	} `json:"tba"`
 	DSN string `json:"dsn" validate:"required"`
 }
 
+// Duration is a time.Duration that is unmarshaled from a JSON string such as "1h30m".
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON parses a duration string.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	d.Duration = duration
+	return nil
+}
+
 // Open parses and validates the JSON config at the given path.
 func Open(path string) (Config, error) {
 	f, err := os.Open(path)
@@ -0,0 +1,100 @@
+// Package export writes event data (reports, team summaries, and matches) as tables in
+// formats that open in spreadsheet programs.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Table is tabular data to export. Cells are nil (empty), strings, float64s, or bools.
+// Sheet names the table where the format supports it.
+type Table struct {
+	Sheet   string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Format is a file format tables can be exported as.
+type Format string
+
+// Export formats.
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ParseFormat parses an export format, defaulting to CSV when empty.
+func ParseFormat(format string) (Format, error) {
+	switch Format(strings.ToLower(format)) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// ContentType returns the MIME type of files in the format.
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Write writes a table to w in the given format.
+func Write(w io.Writer, format Format, table Table) error {
+	if format == FormatXLSX {
+		return WriteXLSX(w, table)
+	}
+	return WriteCSV(w, table)
+}
+
+// WriteCSV writes a table to w as CSV with a header row of the column names. Text starting
+// with a character spreadsheet programs treat as the start of a formula is prefixed with a
+// quote so that it is shown as text instead of evaluated.
+func WriteCSV(w io.Writer, table Table) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(table.Columns); err != nil {
+		return fmt.Errorf("unable to write header: %w", err)
+	}
+
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i := range record {
+			record[i] = ""
+			if i < len(row) {
+				record[i] = csvCell(row[i])
+			}
+		}
+
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("unable to write row: %w", err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
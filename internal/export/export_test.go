@@ -0,0 +1,123 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	testCases := []struct {
+		format    string
+		expected  Format
+		expectErr bool
+	}{
+		{format: "", expected: FormatCSV},
+		{format: "csv", expected: FormatCSV},
+		{format: "XLSX", expected: FormatXLSX},
+		{format: "pdf", expectErr: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.format, func(t *testing.T) {
+			format, err := ParseFormat(tt.format)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("expected error to be %t, but got: %v", tt.expectErr, err)
+			}
+
+			if format != tt.expected {
+				t.Errorf("expected format %q, but got %q", tt.expected, format)
+			}
+		})
+	}
+}
+
+var testTable = Table{
+	Sheet:   "Reports",
+	Columns: []string{"Match", "Cargo", "Climbed", "Comment"},
+	Rows: [][]interface{}{
+		{"qm1", 4.0, true, "good defense, \"fast\""},
+		{"qm2", nil, false, "=HYPERLINK(\"x\")"},
+	},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteCSV(&b, testTable); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "Match,Cargo,Climbed,Comment\n" +
+		"qm1,4,true,\"good defense, \"\"fast\"\"\"\n" +
+		"qm2,,false,\"'=HYPERLINK(\"\"x\"\")\"\n"
+
+	if b.String() != expected {
+		t.Errorf("expected CSV:\n%s\nbut got:\n%s", expected, b.String())
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteXLSX(&b, testTable); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatalf("unable to read workbook: %v", err)
+	}
+
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("unable to open %s: %v", f.Name, err)
+		}
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("unable to read %s: %v", f.Name, err)
+		}
+		parts[f.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("expected workbook to have part %s", name)
+		}
+	}
+
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Reports"`) {
+		t.Errorf("expected sheet to be named Reports, but got workbook: %s", parts["xl/workbook.xml"])
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, cell := range []string{
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">Match</t></is></c>`,
+		`<c r="B2"><v>4</v></c>`,
+		`<c r="C2" t="b"><v>1</v></c>`,
+		`<c r="D2" t="inlineStr"><is><t xml:space="preserve">good defense, &#34;fast&#34;</t></is></c>`,
+		`<c r="C3" t="b"><v>0</v></c>`,
+		`<c r="D3" t="inlineStr"><is><t xml:space="preserve">=HYPERLINK(&#34;x&#34;)</t></is></c>`,
+	} {
+		if !strings.Contains(sheet, cell) {
+			t.Errorf("expected worksheet to contain %s, but got: %s", cell, sheet)
+		}
+	}
+
+	if strings.Contains(sheet, `r="B3"`) {
+		t.Errorf("expected empty cell to be omitted, but got: %s", sheet)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	testCases := map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+
+	for index, expected := range testCases {
+		if name := columnName(index); name != expected {
+			t.Errorf("expected column %d to be named %s, but got %s", index, expected, name)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+)
+
+// Reports returns a table with a row for each report, in the order given. There is a
+// column for every field of the schema that references a report field and isn't hidden.
+// Reports submitted against the schema are migrated to its latest version with the given
+// migrations first.
+func Reports(schema store.Schema, migrations []summary.Migration, reports []store.Report) Table {
+	var fields []store.SchemaField
+	for _, field := range schema.Schema {
+		if field.ReportReference != "" && !field.Hide {
+			fields = append(fields, field)
+		}
+	}
+
+	table := Table{Sheet: "Reports", Columns: []string{"Match", "Team", "Reporter ID"}, Rows: make([][]interface{}, 0, len(reports))}
+	for _, field := range fields {
+		table.Columns = append(table.Columns, field.Name)
+	}
+	table.Columns = append(table.Columns, "Comment")
+
+	for _, report := range reports {
+		var data summary.Report
+		for _, stat := range report.Data {
+			data = append(data, summary.ReportField{Name: stat.Name, Value: stat.Value})
+		}
+		if report.HasSchema(schema.ID) {
+			data = summary.MigrateReport(data, report.SchemaVersion, migrations)
+		}
+
+		values := make(map[string]float64)
+		for _, stat := range data {
+			values[stat.Name] = stat.Value
+		}
+
+		var reporterID interface{}
+		if report.ReporterID != nil {
+			reporterID = float64(*report.ReporterID)
+		}
+
+		row := []interface{}{report.MatchKey, report.TeamKey, reporterID}
+		for _, field := range fields {
+			if value, ok := values[field.ReportReference]; ok {
+				row = append(row, value)
+			} else {
+				row = append(row, nil)
+			}
+		}
+		row = append(row, report.Comment)
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table
+}
+
+// Summaries returns a table with a row for each team's summary, ordered by team number.
+// There are columns for each statistic of every field of the schema that isn't hidden.
+func Summaries(schema store.SchemaFields, summaries map[string]summary.Summary) Table {
+	var fields []store.SchemaField
+	for _, field := range schema {
+		if !field.Hide {
+			fields = append(fields, field)
+		}
+	}
+
+	table := Table{Sheet: "Summaries", Columns: []string{"Team"}, Rows: make([][]interface{}, 0, len(summaries))}
+	for _, field := range fields {
+		for _, stat := range []string{"Avg", "Median", "Min", "Max", "Std Dev", "Trend", "Count"} {
+			table.Columns = append(table.Columns, field.Name+" "+stat)
+		}
+	}
+
+	teams := make([]string, 0, len(summaries))
+	for team := range summaries {
+		teams = append(teams, team)
+	}
+	sortTeamKeys(teams)
+
+	for _, team := range teams {
+		stats := make(map[string]summary.SummaryStat)
+		for _, stat := range summaries[team] {
+			stats[stat.Name] = stat
+		}
+
+		row := []interface{}{team}
+		for _, field := range fields {
+			stat, ok := stats[field.Name]
+			if !ok {
+				row = append(row, nil, nil, nil, nil, nil, nil, nil)
+				continue
+			}
+
+			row = append(row, stat.Average, stat.Median, stat.Min, stat.Max, stat.StdDev, stat.Trend, float64(stat.Count))
+		}
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table
+}
+
+// sortTeamKeys sorts team keys by team number. Keys share the "frc" prefix, so shorter
+// keys have smaller numbers.
+func sortTeamKeys(teams []string) {
+	sort.Slice(teams, func(i, j int) bool {
+		if len(teams[i]) != len(teams[j]) {
+			return len(teams[i]) < len(teams[j])
+		}
+		return teams[i] < teams[j]
+	})
+}
+
+// Matches returns a table with a row for each match, in the order given. Score breakdowns
+// are flattened into a column for every key of either alliance's breakdown, with nested
+// keys joined by periods.
+func Matches(matches []store.Match) Table {
+	var allianceSize int
+	redKeys, blueKeys := make(map[string]bool), make(map[string]bool)
+	red, blue := make([]map[string]interface{}, len(matches)), make([]map[string]interface{}, len(matches))
+
+	for i, match := range matches {
+		if len(match.RedAlliance) > allianceSize {
+			allianceSize = len(match.RedAlliance)
+		}
+		if len(match.BlueAlliance) > allianceSize {
+			allianceSize = len(match.BlueAlliance)
+		}
+
+		red[i], blue[i] = make(map[string]interface{}), make(map[string]interface{})
+		flattenBreakdown(red[i], "", map[string]interface{}(match.RedScoreBreakdown))
+		flattenBreakdown(blue[i], "", map[string]interface{}(match.BlueScoreBreakdown))
+
+		for key := range red[i] {
+			redKeys[key] = true
+		}
+		for key := range blue[i] {
+			blueKeys[key] = true
+		}
+	}
+
+	sortedRedKeys, sortedBlueKeys := sortedKeys(redKeys), sortedKeys(blueKeys)
+
+	table := Table{Sheet: "Matches", Columns: []string{"Match", "Time"}, Rows: make([][]interface{}, 0, len(matches))}
+	for _, alliance := range []string{"Red", "Blue"} {
+		for i := 1; i <= allianceSize; i++ {
+			table.Columns = append(table.Columns, fmt.Sprintf("%s %d", alliance, i))
+		}
+	}
+	table.Columns = append(table.Columns, "Red Score", "Blue Score")
+	for _, key := range sortedRedKeys {
+		table.Columns = append(table.Columns, "Red "+key)
+	}
+	for _, key := range sortedBlueKeys {
+		table.Columns = append(table.Columns, "Blue "+key)
+	}
+
+	for i, match := range matches {
+		row := []interface{}{match.Key, nil}
+		if t := match.GetTime(); t != nil {
+			row[1] = t.UTC().Format(time.RFC3339)
+		}
+
+		for _, alliance := range [][]string{match.RedAlliance, match.BlueAlliance} {
+			for j := 0; j < allianceSize; j++ {
+				if j < len(alliance) {
+					row = append(row, alliance[j])
+				} else {
+					row = append(row, nil)
+				}
+			}
+		}
+
+		row = append(row, score(match.RedScore), score(match.BlueScore))
+
+		for _, key := range sortedRedKeys {
+			row = append(row, red[i][key])
+		}
+		for _, key := range sortedBlueKeys {
+			row = append(row, blue[i][key])
+		}
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table
+}
+
+func score(s *int) interface{} {
+	if s == nil {
+		return nil
+	}
+	return float64(*s)
+}
+
+// flattenBreakdown adds the cells of a score breakdown value decoded from JSON to flat,
+// using prefix and nested keys or list indices joined by periods as their keys.
+func flattenBreakdown(flat map[string]interface{}, prefix string, value interface{}) {
+	key := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, nested := range v {
+			flattenBreakdown(flat, key(k), nested)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			flattenBreakdown(flat, key(strconv.Itoa(i)), nested)
+		}
+	case string, float64, bool:
+		flat[prefix] = v
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+	"github.com/google/go-cmp/cmp"
+	"github.com/lib/pq"
+)
+
+var testSchema = store.SchemaFields{
+	{FieldDescriptor: store.FieldDescriptor{Name: "Cargo"}, ReportReference: "cargo"},
+	{FieldDescriptor: store.FieldDescriptor{Name: "Hatches"}, ReportReference: "hatches", Hide: true},
+	{FieldDescriptor: store.FieldDescriptor{Name: "Climb"}, ReportReference: "climb"},
+	{FieldDescriptor: store.FieldDescriptor{Name: "Score"}, TBAReference: "totalPoints"},
+}
+
+func TestReports(t *testing.T) {
+	reporterID, schemaID, otherSchemaID := int64(3), int64(1), int64(2)
+
+	reports := []store.Report{
+		{
+			MatchKey:      "qm1",
+			TeamKey:       "frc4176",
+			ReporterID:    &reporterID,
+			Data:          store.ReportData{{Name: "cargo", Value: 4}, {Name: "hatches", Value: 2}},
+			Comment:       "fast",
+			SchemaID:      &schemaID,
+			SchemaVersion: 2,
+		},
+		{
+			MatchKey:      "qm2",
+			TeamKey:       "frc254",
+			Data:          store.ReportData{{Name: "balls", Value: 3}, {Name: "climb", Value: 1}},
+			SchemaID:      &schemaID,
+			SchemaVersion: 1,
+		},
+		{
+			MatchKey:      "qm3",
+			TeamKey:       "frc1114",
+			Data:          store.ReportData{{Name: "balls", Value: 5}, {Name: "climb", Value: 0}},
+			SchemaID:      &otherSchemaID,
+			SchemaVersion: 1,
+		},
+	}
+
+	migrations := []summary.Migration{{Version: 2, Rename: map[string]string{"balls": "cargo"}}}
+
+	expected := Table{
+		Sheet:   "Reports",
+		Columns: []string{"Match", "Team", "Reporter ID", "Cargo", "Climb", "Comment"},
+		Rows: [][]interface{}{
+			{"qm1", "frc4176", 3.0, 4.0, nil, "fast"},
+			{"qm2", "frc254", nil, 3.0, 1.0, ""},
+			{"qm3", "frc1114", nil, nil, 0.0, ""},
+		},
+	}
+
+	table := Reports(store.Schema{ID: schemaID, Schema: testSchema}, migrations, reports)
+	if !cmp.Equal(expected, table) {
+		t.Errorf("expected table to equal expected, but got diff: %s", cmp.Diff(expected, table))
+	}
+}
+
+func TestSummaries(t *testing.T) {
+	summaries := map[string]summary.Summary{
+		"frc4176": {
+			{FieldDescriptor: summary.FieldDescriptor{Name: "Cargo"}, Max: 6, Average: 4, Min: 2, Median: 4, StdDev: 2, Trend: 1, Count: 3},
+			{FieldDescriptor: summary.FieldDescriptor{Name: "Hatches"}, Max: 2, Average: 2, Min: 2, Median: 2, Count: 1},
+		},
+		"frc254": {
+			{FieldDescriptor: summary.FieldDescriptor{Name: "Cargo"}, Max: 9, Average: 9, Min: 9, Median: 9, Count: 1},
+			{FieldDescriptor: summary.FieldDescriptor{Name: "Score"}, Max: 80, Average: 70, Min: 60, Median: 70, StdDev: 10, Trend: -5, Count: 3},
+		},
+	}
+
+	expected := Table{
+		Sheet: "Summaries",
+		Columns: []string{
+			"Team",
+			"Cargo Avg", "Cargo Median", "Cargo Min", "Cargo Max", "Cargo Std Dev", "Cargo Trend", "Cargo Count",
+			"Climb Avg", "Climb Median", "Climb Min", "Climb Max", "Climb Std Dev", "Climb Trend", "Climb Count",
+			"Score Avg", "Score Median", "Score Min", "Score Max", "Score Std Dev", "Score Trend", "Score Count",
+		},
+		Rows: [][]interface{}{
+			{
+				"frc254",
+				9.0, 9.0, 9.0, 9.0, 0.0, 0.0, 1.0,
+				nil, nil, nil, nil, nil, nil, nil,
+				70.0, 70.0, 60.0, 80.0, 10.0, -5.0, 3.0,
+			},
+			{
+				"frc4176",
+				4.0, 4.0, 2.0, 6.0, 2.0, 1.0, 3.0,
+				nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil,
+			},
+		},
+	}
+
+	table := Summaries(testSchema, summaries)
+	if !cmp.Equal(expected, table) {
+		t.Errorf("expected table to equal expected, but got diff: %s", cmp.Diff(expected, table))
+	}
+}
+
+func TestMatches(t *testing.T) {
+	playedAt := time.Date(2019, 3, 7, 15, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	redScore, blueScore := 60, 72
+
+	matches := []store.Match{
+		{
+			Key:          "qm1",
+			ActualTime:   &playedAt,
+			RedScore:     &redScore,
+			BlueScore:    &blueScore,
+			RedAlliance:  pq.StringArray{"frc1", "frc2", "frc3"},
+			BlueAlliance: pq.StringArray{"frc4", "frc5", "frc6"},
+			RedScoreBreakdown: store.ScoreBreakdown{
+				"totalPoints":   60.0,
+				"habLineRobot1": "CrossedHabLineInSandstorm",
+				"rocket":        map[string]interface{}{"near": true},
+			},
+			BlueScoreBreakdown: store.ScoreBreakdown{
+				"totalPoints": 72.0,
+				"bays":        []interface{}{"Panel", "None"},
+			},
+		},
+		{
+			Key:          "qm2",
+			RedAlliance:  pq.StringArray{"frc7", "frc8"},
+			BlueAlliance: pq.StringArray{"frc9", "frc10", "frc11"},
+		},
+	}
+
+	expected := Table{
+		Sheet: "Matches",
+		Columns: []string{
+			"Match", "Time", "Red 1", "Red 2", "Red 3", "Blue 1", "Blue 2", "Blue 3", "Red Score", "Blue Score",
+			"Red habLineRobot1", "Red rocket.near", "Red totalPoints",
+			"Blue bays.0", "Blue bays.1", "Blue totalPoints",
+		},
+		Rows: [][]interface{}{
+			{
+				"qm1", "2019-03-07T20:30:00Z", "frc1", "frc2", "frc3", "frc4", "frc5", "frc6", 60.0, 72.0,
+				"CrossedHabLineInSandstorm", true, 60.0,
+				"Panel", "None", 72.0,
+			},
+			{
+				"qm2", nil, "frc7", "frc8", nil, "frc9", "frc10", "frc11", nil, nil,
+				nil, nil, nil,
+				nil, nil, nil,
+			},
+		},
+	}
+
+	table := Matches(matches)
+	if !cmp.Equal(expected, table) {
+		t.Errorf("expected table to equal expected, but got diff: %s", cmp.Diff(expected, table))
+	}
+}
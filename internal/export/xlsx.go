@@ -0,0 +1,153 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The parts of a workbook other than its worksheet, which never change.
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+// WriteXLSX writes a table to w as an Office Open XML workbook with a single worksheet,
+// with a header row of the column names.
+func WriteXLSX(w io.Writer, table Table) error {
+	zw := zip.NewWriter(w)
+
+	for _, part := range xlsxParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("unable to create %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return fmt.Errorf("unable to write %s: %w", part.name, err)
+		}
+	}
+
+	f, err := zw.Create("xl/workbook.xml")
+	if err != nil {
+		return fmt.Errorf("unable to create workbook: %w", err)
+	}
+	if _, err := io.WriteString(f, xml.Header+`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="`+xmlEscape(sheetName(table.Sheet))+`" sheetId="1" r:id="rId1"/></sheets></workbook>`); err != nil {
+		return fmt.Errorf("unable to write workbook: %w", err)
+	}
+
+	f, err = zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("unable to create worksheet: %w", err)
+	}
+	if err := writeWorksheet(f, table); err != nil {
+		return fmt.Errorf("unable to write worksheet: %w", err)
+	}
+
+	return zw.Close()
+}
+
+func writeWorksheet(w io.Writer, table Table) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	header := make([]interface{}, len(table.Columns))
+	for i, column := range table.Columns {
+		header[i] = column
+	}
+	writeRow(bw, 1, header)
+
+	for i, row := range table.Rows {
+		writeRow(bw, i+2, row)
+	}
+
+	bw.WriteString(`</sheetData></worksheet>`)
+
+	return bw.Flush()
+}
+
+func writeRow(w *bufio.Writer, number int, cells []interface{}) {
+	row := strconv.Itoa(number)
+	w.WriteString(`<row r="` + row + `">`)
+
+	for i, value := range cells {
+		ref := columnName(i) + row
+
+		switch v := value.(type) {
+		case string:
+			w.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">` + xmlEscape(v) + `</t></is></c>`)
+		case float64:
+			w.WriteString(`<c r="` + ref + `"><v>` + strconv.FormatFloat(v, 'f', -1, 64) + `</v></c>`)
+		case bool:
+			b := "0"
+			if v {
+				b = "1"
+			}
+			w.WriteString(`<c r="` + ref + `" t="b"><v>` + b + `</v></c>`)
+		}
+	}
+
+	w.WriteString(`</row>`)
+}
+
+// columnName returns the letters naming a zero-indexed spreadsheet column (A, B, ..., Z,
+// AA, AB, ...).
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// sheetName returns a name usable as a worksheet name, which can't be empty, contain
+// []:*?/\, or be longer than 31 characters.
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if name == "" {
+		return "Sheet1"
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		return string(runes[:31])
+	}
+	return name
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,330 @@
+// Package importer imports historical scouting reports, such as paper scouting data typed
+// into spreadsheets, from CSV files.
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+// ErrInvalidCSV is returned when a CSV file can't be read with a mapping at all, as opposed
+// to individual rows being invalid.
+var ErrInvalidCSV = errors.New("invalid CSV")
+
+// Mapping describes which CSV columns hold each part of a report. Fields maps column names
+// to the ReportReference names of the event schema. Columns that aren't mapped are ignored.
+type Mapping struct {
+	EventKey string            `json:"eventKey" validate:"required"`
+	MatchKey string            `json:"matchKey" validate:"required"`
+	TeamKey  string            `json:"teamKey" validate:"required"`
+	Comment  string            `json:"comment,omitempty"`
+	Fields   map[string]string `json:"fields" validate:"required,min=1"`
+}
+
+// ParseMapping parses and validates a JSON mapping.
+func ParseMapping(r io.Reader) (Mapping, error) {
+	var m Mapping
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return m, fmt.Errorf("unable to decode mapping: %w", err)
+	}
+
+	if err := validator.New().Struct(m); err != nil {
+		return m, fmt.Errorf("invalid mapping: %w", err)
+	}
+
+	return m, nil
+}
+
+// RowError describes why a row of a CSV file can't be imported. Row is the line number of
+// the row, where the header is row 1.
+type RowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// Options are the options of an import. Every report is stored as submitted by the
+// reporter in the realm.
+type Options struct {
+	RealmID    int64
+	ReporterID int64
+	DryRun     bool
+}
+
+// Result is the result of an import. When there are any errors, no reports are imported.
+type Result struct {
+	Imported int        `json:"imported"`
+	DryRun   bool       `json:"dryRun"`
+	Errors   []RowError `json:"errors"`
+}
+
+type row struct {
+	number int
+	report store.Report
+}
+
+// Import reads reports from a CSV file with a header row using the mapping, validates them,
+// and stores them in a single transaction. Events must be visible to the realm, matches
+// must be at the event, teams must be in the match, and fields must be report fields of
+// the event's schema, if it has one. Reports replace the reporter's existing reports for
+// the same team and match. If any row is invalid, nothing is stored and the errors of every
+// row are returned in the result. In a dry run nothing is stored either way.
+func Import(ctx context.Context, sto *store.Service, r io.Reader, mapping Mapping, opts Options) (Result, error) {
+	result := Result{DryRun: opts.DryRun}
+
+	rows, rowErrs, err := readRows(r, mapping)
+	if err != nil {
+		return result, err
+	}
+
+	v := validation{sto: sto, realmID: opts.RealmID, events: make(map[string]*eventInfo)}
+
+	reports := make([]store.Report, 0, len(rows))
+	for _, row := range rows {
+		report := row.report
+		report.RealmID = &opts.RealmID
+		report.ReporterID = &opts.ReporterID
+
+		schema, reason, err := v.validate(ctx, report)
+		if err != nil {
+			return result, err
+		}
+		if reason != "" {
+			rowErrs = append(rowErrs, RowError{Row: row.number, Error: reason})
+			continue
+		}
+		report.SetSchema(schema)
+
+		reports = append(reports, report)
+	}
+
+	sort.SliceStable(rowErrs, func(i, j int) bool { return rowErrs[i].Row < rowErrs[j].Row })
+	result.Errors = rowErrs
+	if len(rowErrs) != 0 {
+		return result, nil
+	}
+
+	if err := sto.ImportReports(ctx, reports, opts.DryRun); err != nil {
+		return result, fmt.Errorf("unable to import reports: %w", err)
+	}
+	result.Imported = len(reports)
+
+	return result, nil
+}
+
+// readRows reads reports from a CSV file with a header row using the mapping. Rows that
+// can't be read are returned as row errors.
+func readRows(r io.Reader, mapping Mapping) ([]row, []RowError, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("%w: no header row", ErrInvalidCSV)
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[strings.TrimSpace(column)] = i
+	}
+
+	required := []string{mapping.EventKey, mapping.MatchKey, mapping.TeamKey}
+	if mapping.Comment != "" {
+		required = append(required, mapping.Comment)
+	}
+	for column := range mapping.Fields {
+		required = append(required, column)
+	}
+	for _, column := range required {
+		if _, ok := columns[column]; !ok {
+			return nil, nil, fmt.Errorf("%w: no column %q", ErrInvalidCSV, column)
+		}
+	}
+
+	rows := make([]row, 0)
+	rowErrs := make([]RowError, 0)
+	seen := make(map[string]int)
+
+	for number := 2; ; number++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+
+		if len(record) != len(header) {
+			rowErrs = append(rowErrs, RowError{Row: number, Error: fmt.Sprintf("expected %d columns, got %d", len(header), len(record))})
+			continue
+		}
+
+		report, reason := readReport(header, record, columns, mapping)
+		if reason != "" {
+			rowErrs = append(rowErrs, RowError{Row: number, Error: reason})
+			continue
+		}
+
+		key := report.EventKey + "_" + report.MatchKey + "_" + report.TeamKey
+		if first, ok := seen[key]; ok {
+			rowErrs = append(rowErrs, RowError{Row: number, Error: fmt.Sprintf("duplicate of row %d", first)})
+			continue
+		}
+		seen[key] = number
+
+		rows = append(rows, row{number: number, report: report})
+	}
+
+	return rows, rowErrs, nil
+}
+
+// readReport reads a report from a CSV record, returning the reason the record isn't a
+// valid report if it isn't. Keys are normalized, so that match keys may include the event
+// key, and team keys may be just the team number. Empty field values are left out.
+func readReport(header, record []string, columns map[string]int, mapping Mapping) (store.Report, string) {
+	value := func(column string) string { return strings.TrimSpace(record[columns[column]]) }
+
+	report := store.Report{
+		EventKey: strings.ToLower(value(mapping.EventKey)),
+		MatchKey: strings.ToLower(value(mapping.MatchKey)),
+		TeamKey:  strings.ToLower(value(mapping.TeamKey)),
+		Data:     store.ReportData{},
+	}
+
+	if report.EventKey == "" || report.MatchKey == "" || report.TeamKey == "" {
+		return report, "missing event, match, or team key"
+	}
+
+	report.MatchKey = strings.TrimPrefix(report.MatchKey, report.EventKey+"_")
+	if _, err := strconv.Atoi(report.TeamKey); err == nil {
+		report.TeamKey = "frc" + report.TeamKey
+	}
+
+	if mapping.Comment != "" {
+		report.Comment = value(mapping.Comment)
+	}
+
+	// Fields are read in column order so that report data is in the same order as the file.
+	for i, column := range header {
+		name, ok := mapping.Fields[strings.TrimSpace(column)]
+		if !ok || strings.TrimSpace(record[i]) == "" {
+			continue
+		}
+
+		v, ok := parseValue(strings.TrimSpace(record[i]))
+		if !ok {
+			return report, fmt.Sprintf("column %q: invalid value %q", strings.TrimSpace(column), strings.TrimSpace(record[i]))
+		}
+
+		report.Data = append(report.Data, store.Stat{Name: name, Value: v})
+	}
+
+	return report, ""
+}
+
+// parseValue parses a number, or a boolean (true/false, yes/no, y/n, or x for checked
+// boxes) as 1 or 0.
+func parseValue(s string) (float64, bool) {
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, true
+	}
+
+	switch strings.ToLower(s) {
+	case "true", "yes", "y", "x":
+		return 1, true
+	case "false", "no", "n":
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// eventInfo holds what reports at an event are validated against.
+type eventInfo struct {
+	exists  bool
+	schema  store.Schema
+	matches map[string]map[string]bool
+}
+
+// validation validates reports against the store, retrieving each event's info once.
+type validation struct {
+	sto     *store.Service
+	realmID int64
+	events  map[string]*eventInfo
+}
+
+// validate returns the schema of a report's event, and the reason the report is
+// invalid if it is.
+func (v *validation) validate(ctx context.Context, report store.Report) (store.Schema, string, error) {
+	info, err := v.event(ctx, report.EventKey)
+	if err != nil {
+		return store.Schema{}, "", err
+	}
+
+	if !info.exists {
+		return store.Schema{}, fmt.Sprintf("event %s does not exist", report.EventKey), nil
+	}
+
+	teams, ok := info.matches[report.MatchKey]
+	if !ok {
+		return store.Schema{}, fmt.Sprintf("match %s does not exist at event %s", report.MatchKey, report.EventKey), nil
+	}
+	if !teams[report.TeamKey] {
+		return store.Schema{}, fmt.Sprintf("team %s is not in match %s", report.TeamKey, report.MatchKey), nil
+	}
+
+	if fieldErrs := store.ValidateReportData(info.schema.Schema, report.Data); len(fieldErrs) != 0 {
+		return store.Schema{}, store.ReportFieldErrorsReason(fieldErrs), nil
+	}
+
+	return info.schema, "", nil
+}
+
+func (v *validation) event(ctx context.Context, eventKey string) (*eventInfo, error) {
+	if info, ok := v.events[eventKey]; ok {
+		return info, nil
+	}
+
+	info := &eventInfo{matches: make(map[string]map[string]bool)}
+	v.events[eventKey] = info
+
+	event, err := v.sto.GetEventForRealm(ctx, eventKey, &v.realmID)
+	if errors.Is(err, store.ErrNoResults{}) {
+		return info, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to retrieve event %s: %w", eventKey, err)
+	}
+	info.exists = true
+
+	if event.SchemaID != nil {
+		info.schema, err = v.sto.GetSchemaByID(ctx, *event.SchemaID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve schema of event %s: %w", eventKey, err)
+		}
+	}
+
+	matches, err := v.sto.GetMatchesForRealm(ctx, eventKey, nil, false, &v.realmID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve matches of event %s: %w", eventKey, err)
+	}
+
+	for _, match := range matches {
+		teams := make(map[string]bool)
+		for _, team := range append([]string(match.RedAlliance), match.BlueAlliance...) {
+			teams[team] = true
+		}
+		info.matches[match.Key] = teams
+	}
+
+	return info, nil
+}
@@ -0,0 +1,147 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseMapping(t *testing.T) {
+	testCases := []struct {
+		name      string
+		mapping   string
+		expectErr bool
+	}{
+		{
+			name:    "valid",
+			mapping: `{"eventKey": "Event", "matchKey": "Match", "teamKey": "Team", "fields": {"Cargo": "cargo"}}`,
+		},
+		{
+			name:      "no fields",
+			mapping:   `{"eventKey": "Event", "matchKey": "Match", "teamKey": "Team", "fields": {}}`,
+			expectErr: true,
+		},
+		{
+			name:      "no team key",
+			mapping:   `{"eventKey": "Event", "matchKey": "Match", "fields": {"Cargo": "cargo"}}`,
+			expectErr: true,
+		},
+		{
+			name:      "invalid JSON",
+			mapping:   `{"eventKey":`,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMapping(strings.NewReader(tt.mapping))
+			if tt.expectErr != (err != nil) {
+				t.Errorf("expected error to be %t, but got: %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestReadRows(t *testing.T) {
+	mapping := Mapping{
+		EventKey: "Event",
+		MatchKey: "Match",
+		TeamKey:  "Team",
+		Comment:  "Notes",
+		Fields:   map[string]string{"Cargo": "cargo", "Climbed": "climb"},
+	}
+
+	testCases := []struct {
+		name            string
+		csv             string
+		expectedRows    []row
+		expectedRowErrs []RowError
+		expectErr       bool
+	}{
+		{
+			name: "valid",
+			csv: "Event,Match,Team,Climbed,Cargo,Notes,Scout\n" +
+				"2019flor,qm1,4176,x,4,fast,Josiah\n" +
+				"2019FLOR,2019flor_qm2,frc254, no ,,,Josiah\n",
+			expectedRows: []row{
+				{
+					number: 2,
+					report: store.Report{
+						EventKey: "2019flor",
+						MatchKey: "qm1",
+						TeamKey:  "frc4176",
+						Data:     store.ReportData{{Name: "climb", Value: 1}, {Name: "cargo", Value: 4}},
+						Comment:  "fast",
+					},
+				},
+				{
+					number: 3,
+					report: store.Report{
+						EventKey: "2019flor",
+						MatchKey: "qm2",
+						TeamKey:  "frc254",
+						Data:     store.ReportData{{Name: "climb", Value: 0}},
+					},
+				},
+			},
+			expectedRowErrs: []RowError{},
+		},
+		{
+			name: "invalid rows",
+			csv: "Event,Match,Team,Climbed,Cargo,Notes\n" +
+				"2019flor,qm1,4176,x,lots,\n" +
+				"2019flor,qm1,\n" +
+				"2019flor,,4176,,,\n" +
+				"2019flor,qm2,4176,,,\n" +
+				"2019flor,qm2,frc4176,,,\n",
+			expectedRows: []row{
+				{
+					number: 5,
+					report: store.Report{EventKey: "2019flor", MatchKey: "qm2", TeamKey: "frc4176", Data: store.ReportData{}},
+				},
+			},
+			expectedRowErrs: []RowError{
+				{Row: 2, Error: `column "Cargo": invalid value "lots"`},
+				{Row: 3, Error: "expected 6 columns, got 3"},
+				{Row: 4, Error: "missing event, match, or team key"},
+				{Row: 6, Error: "duplicate of row 5"},
+			},
+		},
+		{
+			name:      "missing column",
+			csv:       "Event,Match,Team,Cargo,Notes\n",
+			expectErr: true,
+		},
+		{
+			name:      "empty",
+			csv:       "",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, rowErrs, err := readRows(strings.NewReader(tt.csv), mapping)
+			if tt.expectErr {
+				if !errors.Is(err, ErrInvalidCSV) {
+					t.Errorf("expected ErrInvalidCSV, but got: %v", err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !cmp.Equal(tt.expectedRows, rows, cmp.AllowUnexported(row{})) {
+				t.Errorf("expected rows to equal expected, but got diff: %s", cmp.Diff(tt.expectedRows, rows, cmp.AllowUnexported(row{})))
+			}
+
+			if !cmp.Equal(tt.expectedRowErrs, rowErrs) {
+				t.Errorf("expected row errors to equal expected, but got diff: %s", cmp.Diff(tt.expectedRowErrs, rowErrs))
+			}
+		})
+	}
+}
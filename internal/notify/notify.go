@@ -0,0 +1,129 @@
+// Package notify fans out messages about changes to an event's data (e.g. new match
+// scores from TBA or a new report) to subscribers such as the server's event streams.
+package notify
+
+import "sync"
+
+// Message types.
+const (
+	TypeMatches  = "matches"
+	TypeRankings = "rankings"
+	TypeReport   = "report"
+)
+
+// Message notifies subscribers that some data for an event has changed. It only says
+// what changed so that clients fetch the data itself through the REST API.
+type Message struct {
+	ID       int64  `json:"-"`
+	Type     string `json:"type"`
+	EventKey string `json:"eventKey"`
+	MatchKey string `json:"matchKey,omitempty"`
+	TeamKey  string `json:"teamKey,omitempty"`
+
+	// RealmID is the realm the change was made by, or nil for data from TBA.
+	RealmID *int64 `json:"-"`
+	// Shared is whether the realm the change was made by shares its reports.
+	Shared bool `json:"-"`
+}
+
+// VisibleTo returns whether a user in the given realm (nil if not logged in) is allowed
+// to see a message. Messages from TBA or from realms that share their reports are visible
+// to everyone.
+func (m Message) VisibleTo(realmID *int64) bool {
+	if m.RealmID == nil || m.Shared {
+		return true
+	}
+
+	return realmID != nil && *realmID == *m.RealmID
+}
+
+const (
+	// bufferSize is the number of messages a subscriber may fall behind by before it
+	// starts missing them.
+	bufferSize = 16
+	// historySize is the number of recent messages kept for replaying to reconnecting
+	// subscribers.
+	historySize = 64
+)
+
+// Broker sends published messages to the subscribers of the message's event. A nil
+// *Broker is valid and discards all messages.
+type Broker struct {
+	mu          sync.Mutex
+	lastID      int64
+	history     []Message
+	subscribers map[chan Message]string
+}
+
+// NewBroker creates a broker with no subscribers.
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[chan Message]string)}
+}
+
+// Publish assigns a message the next ID and sends it to every subscriber of its event.
+// Subscribers that aren't keeping up miss the message rather than blocking the publisher.
+func (b *Broker) Publish(msg Message) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	msg.ID = b.lastID
+
+	b.history = append(b.history, msg)
+	if len(b.history) > historySize {
+		b.history = b.history[len(b.history)-historySize:]
+	}
+
+	for ch, eventKey := range b.subscribers {
+		if eventKey != msg.EventKey {
+			continue
+		}
+
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of messages for an event and a function to unsubscribe.
+// If lastID is set, any retained messages for the event published after it are sent
+// first so that reconnecting subscribers don't miss anything.
+func (b *Broker) Subscribe(eventKey string, lastID int64) (<-chan Message, func()) {
+	if b == nil {
+		return nil, func() {}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Message, bufferSize)
+
+	// an ID from before the broker was created (e.g. the server restarted) can't be
+	// replayed from
+	if lastID > 0 && lastID <= b.lastID {
+		for _, msg := range b.history {
+			if msg.ID <= lastID || msg.EventKey != eventKey {
+				continue
+			}
+
+			select {
+			case ch <- msg:
+			default:
+			}
+		}
+	}
+
+	b.subscribers[ch] = eventKey
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subscribers, ch)
+	}
+}
@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newRealmID(id int64) *int64 {
+	return &id
+}
+
+func receive(ch <-chan Message) []Message {
+	messages := make([]Message, 0)
+	for {
+		select {
+		case msg := <-ch:
+			messages = append(messages, msg)
+		default:
+			return messages
+		}
+	}
+}
+
+func TestBroker(t *testing.T) {
+	b := NewBroker()
+
+	abca, unsubscribeABCA := b.Subscribe("2019abca", 0)
+	flor, unsubscribeFlor := b.Subscribe("2019flor", 0)
+	defer unsubscribeFlor()
+
+	b.Publish(Message{Type: TypeMatches, EventKey: "2019abca"})
+	b.Publish(Message{Type: TypeRankings, EventKey: "2019flor"})
+
+	expectedABCA := []Message{{ID: 1, Type: TypeMatches, EventKey: "2019abca"}}
+	if got := receive(abca); !cmp.Equal(got, expectedABCA) {
+		t.Errorf("expected messages to match, but got diff: %s", cmp.Diff(expectedABCA, got))
+	}
+
+	expectedFlor := []Message{{ID: 2, Type: TypeRankings, EventKey: "2019flor"}}
+	if got := receive(flor); !cmp.Equal(got, expectedFlor) {
+		t.Errorf("expected messages to match, but got diff: %s", cmp.Diff(expectedFlor, got))
+	}
+
+	unsubscribeABCA()
+	b.Publish(Message{Type: TypeReport, EventKey: "2019abca", MatchKey: "2019abca_qm1", TeamKey: "frc1"})
+	if got := receive(abca); len(got) != 0 {
+		t.Errorf("expected no messages after unsubscribing but got: %v", got)
+	}
+
+	replayed, unsubscribeReplayed := b.Subscribe("2019abca", 1)
+	defer unsubscribeReplayed()
+
+	expectedReplayed := []Message{{ID: 3, Type: TypeReport, EventKey: "2019abca", MatchKey: "2019abca_qm1", TeamKey: "frc1"}}
+	if got := receive(replayed); !cmp.Equal(got, expectedReplayed) {
+		t.Errorf("expected replayed messages to match, but got diff: %s", cmp.Diff(expectedReplayed, got))
+	}
+
+	future, unsubscribeFuture := b.Subscribe("2019abca", 100)
+	defer unsubscribeFuture()
+
+	if got := receive(future); len(got) != 0 {
+		t.Errorf("expected no replay for an unknown ID but got: %v", got)
+	}
+}
+
+func TestMessageVisibleTo(t *testing.T) {
+	testCases := []struct {
+		name     string
+		msg      Message
+		realmID  *int64
+		expected bool
+	}{
+		{name: "tba message logged out", msg: Message{}, expected: true},
+		{name: "tba message logged in", msg: Message{}, realmID: newRealmID(1), expected: true},
+		{name: "private report logged out", msg: Message{RealmID: newRealmID(1)}, expected: false},
+		{name: "private report same realm", msg: Message{RealmID: newRealmID(1)}, realmID: newRealmID(1), expected: true},
+		{name: "private report other realm", msg: Message{RealmID: newRealmID(1)}, realmID: newRealmID(2), expected: false},
+		{name: "shared report other realm", msg: Message{RealmID: newRealmID(1), Shared: true}, realmID: newRealmID(2), expected: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.VisibleTo(tt.realmID); got != tt.expected {
+				t.Errorf("expected visible to be %v but got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -5,19 +5,37 @@ package refresh
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/npmanos/4176Gameday-backend/internal/analysis"
+	"github.com/npmanos/4176Gameday-backend/internal/assignment"
+	"github.com/npmanos/4176Gameday-backend/internal/notify"
 	"github.com/npmanos/4176Gameday-backend/internal/store"
 	"github.com/npmanos/4176Gameday-backend/internal/tba"
 	"github.com/sirupsen/logrus"
 )
 
-// Service updates the store by polling TBA for the current year.
+// Service updates the store by polling TBA for the current year. Each event's matches are
+// polled on a schedule that follows its matches, and its rankings and alliances are polled
+// along with them while it is active. When Webhooks is set, TBA is expected to notify the
+// server of match and alliance changes, and events are polled less often around matches
+// as a fallback. Events are active on match days from ActiveEventBuffer before the day's
+// first match until ActiveEventBuffer after its last match.
 type Service struct {
-	TBA    *tba.Service
-	Store  *store.Service
-	Logger *logrus.Logger
-	Year   int
+	TBA               *tba.Service
+	Store             *store.Service
+	Broker            *notify.Broker
+	Logger            *logrus.Logger
+	Year              int
+	Webhooks          bool
+	ActiveEventBuffer time.Duration
+
+	pollsMu      sync.Mutex
+	pollEvents   map[string]store.Event
+	activeEvents map[string]bool
+	nextPolls    map[string]time.Time
 }
 
 type eventMatches struct {
@@ -25,41 +43,66 @@ type eventMatches struct {
 	Matches  []store.Match
 }
 
+const (
+	// pollInterval is how often events are polled around their next match.
+	pollInterval = time.Second * 30
+	// webhookPollInterval is how often events are polled around their next match when
+	// webhooks are enabled.
+	webhookPollInterval = time.Minute * 5
+)
+
 // Run starts the TBA updater service that will:
-// * Update all events for the configured year, including matches, and rankings, every 15 minutes.
+// * Update all events for the configured year every 15 minutes.
 // * Update all teams every day.
-// * Update all active event matches and rankings every 15 seconds.
+// * Update each active event's matches, rankings, and alliances every 30 seconds (or 5
+//   minutes when webhooks are enabled) from ActiveEventBuffer before its next match, backing
+//   off between matches. Only the matches of events that aren't active are polled, every 6
+//   hours, and events are polled right away once they become active. Polls wait for TBA's
+//   cached matches to expire.
+// * Recalculate event OPRs whenever stored match scores change, or when none are stored.
+// * Remake scout assignments whenever the match schedule changes.
 func (s *Service) Run(ctx context.Context) {
 	const (
-		eventsInterval = time.Minute * 15
-		activeInterval = time.Second * 30
-		teamsInterval  = time.Hour * 24
+		eventsInterval   = time.Minute * 15
+		teamsInterval    = time.Hour * 24
+		scheduleInterval = time.Second * 5
+		activeInterval   = time.Second * 30
 	)
 
 	events := make(chan []store.Event)
 	storeEvents := make(chan []store.Event)
 	matchEvents := make(chan string)
 	rankingEvents := make(chan string)
-	activeEvents := make(chan string)
+	allianceEvents := make(chan string)
+	scheduledPolls := make(chan scheduledPoll)
 
 	go func() {
 		defer func() {
 			close(storeEvents)
 			close(matchEvents)
 			close(rankingEvents)
+			close(allianceEvents)
 		}()
 
 		for {
 			select {
-			case event := <-activeEvents:
-				matchEvents <- event
-				rankingEvents <- event
-			case eventGroup := <-events:
-				storeEvents <- eventGroup
-				for _, event := range eventGroup {
-					matchEvents <- event.Key
-					rankingEvents <- event.Key
+			case poll, ok := <-scheduledPolls:
+				if !ok {
+					return
+				}
+
+				matchEvents <- poll.eventKey
+				if poll.active {
+					rankingEvents <- poll.eventKey
+					allianceEvents <- poll.eventKey
 				}
+			case eventGroup, ok := <-events:
+				if !ok {
+					return
+				}
+
+				storeEvents <- eventGroup
+				s.scheduleEventGroup(eventGroup)
 			case <-ctx.Done():
 				return
 			}
@@ -68,7 +111,7 @@ func (s *Service) Run(ctx context.Context) {
 
 	go s.fetchEvents(ctx, eventsInterval, events)
 	go s.storeEvents(ctx, storeEvents)
-	go s.seedActiveEvents(ctx, activeInterval, activeEvents)
+	go s.scheduleEvents(ctx, scheduleInterval, activeInterval, scheduledPolls)
 
 	teams := make(chan []store.Team)
 	go s.fetchTeams(ctx, teamsInterval, teams)
@@ -78,6 +121,10 @@ func (s *Service) Run(ctx context.Context) {
 	go s.fetchMatches(ctx, matchEvents, matches)
 	go s.storeMatches(ctx, matches)
 
+	alliances := make(chan store.AllianceSelection)
+	go s.fetchAlliances(ctx, allianceEvents, alliances)
+	go s.storeAlliances(ctx, alliances)
+
 	rankings := make(chan []store.EventTeam)
 	go s.fetchRankings(ctx, rankingEvents, rankings)
 	s.storeRankings(ctx, rankings)
@@ -121,44 +168,6 @@ func (s *Service) fetchEvents(ctx context.Context, interval time.Duration, event
 	}
 }
 
-func (s *Service) seedActiveEvents(ctx context.Context, interval time.Duration, events chan<- string) {
-	const timeout = time.Second * 10
-
-	activeTicker := time.NewTicker(interval)
-
-	defer func() {
-		activeTicker.Stop()
-		close(events)
-	}()
-
-	getEvents := func() {
-		timeoutContext, cancel := context.WithTimeout(ctx, timeout)
-		defer cancel()
-
-		activeEvents, err := s.Store.GetActiveEvents(timeoutContext)
-		if err != nil {
-			s.Logger.WithError(err).Errorf("unable get active events %d", s.Year)
-			return
-		}
-
-		for _, event := range activeEvents {
-			events <- event
-		}
-
-		s.Logger.WithField("count", len(activeEvents)).Info("sent active events")
-	}
-
-	getEvents()
-	for {
-		select {
-		case <-activeTicker.C:
-			getEvents()
-		case <-ctx.Done():
-			return
-		}
-	}
-}
-
 func (s *Service) storeEvents(ctx context.Context, events <-chan []store.Event) {
 	const timeout = time.Second * 10
 
@@ -278,19 +287,9 @@ func (s *Service) storeMatches(ctx context.Context, matches <-chan eventMatches)
 		timeoutContext, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
-		err := s.Store.UpdateTBAMatches(timeoutContext, m.Matches)
-		if err != nil {
-			s.Logger.WithError(err).Errorf("unable to upsert matches")
-			return
-		}
-
-		err = s.Store.MarkMatchesDeleted(ctx, m.EventKey, m.Matches)
-		if err != nil {
-			s.Logger.WithError(err).Errorf("unable to mark matches deleted matches")
-			return
+		if err := s.updateMatches(timeoutContext, m); err != nil {
+			s.Logger.WithError(err).Error("unable to store matches")
 		}
-
-		s.Logger.WithField("count", len(m.Matches)).Info("stored matches")
 	}
 
 	for m := range matches {
@@ -298,6 +297,63 @@ func (s *Service) storeMatches(ctx context.Context, matches <-chan eventMatches)
 	}
 }
 
+// updateMatches stores all of an event's TBA matches, marking stored matches TBA no longer
+// has as deleted, then updates what depends on them. OPRs are recalculated when scores
+// change, or when none are stored for the event yet, such as for events stored before OPRs
+// were calculated.
+func (s *Service) updateMatches(ctx context.Context, m eventMatches) error {
+	scoresChanged, err := s.Store.UpdateTBAMatches(ctx, m.Matches)
+	if err != nil {
+		return fmt.Errorf("unable to upsert matches: %w", err)
+	}
+
+	err = s.Store.MarkMatchesDeleted(ctx, m.EventKey, m.Matches)
+	if err != nil {
+		return fmt.Errorf("unable to mark matches deleted: %w", err)
+	}
+
+	s.Logger.WithField("count", len(m.Matches)).Info("stored matches")
+
+	s.Broker.Publish(notify.Message{Type: notify.TypeMatches, EventKey: m.EventKey})
+
+	if scoresChanged || s.missingOPRs(ctx, m.EventKey) {
+		s.updateOPRs(ctx, m)
+	}
+
+	s.updateScoutSchedules(ctx, m.EventKey)
+
+	return nil
+}
+
+// missingOPRs returns whether no OPRs are stored for an event.
+func (s *Service) missingOPRs(ctx context.Context, eventKey string) bool {
+	oprs, err := s.Store.GetEventOPRs(ctx, eventKey)
+	if err != nil {
+		s.Logger.WithError(err).Errorf("unable to retrieve oprs for event %q", eventKey)
+		return false
+	}
+
+	return len(oprs) == 0
+}
+
+// updateOPRs recalculates and stores the OPRs for an event from all of its TBA matches.
+func (s *Service) updateOPRs(ctx context.Context, m eventMatches) {
+	oprs, err := analysis.EventOPRs(m.EventKey, m.Matches)
+	if errors.Is(err, analysis.ErrNotEnoughMatches) {
+		return
+	} else if err != nil {
+		s.Logger.WithError(err).Errorf("unable to calculate oprs for event %q", m.EventKey)
+		return
+	}
+
+	if err := s.Store.SetEventOPRs(ctx, m.EventKey, oprs); err != nil {
+		s.Logger.WithError(err).Errorf("unable to store oprs")
+		return
+	}
+
+	s.Logger.WithField("eventKey", m.EventKey).WithField("count", len(oprs)).Info("stored oprs")
+}
+
 func (s *Service) fetchRankings(ctx context.Context, eventKeys <-chan string, rankings chan<- []store.EventTeam) {
 	const timeout = time.Second * 10
 
@@ -334,16 +390,136 @@ func (s *Service) storeRankings(ctx context.Context, rankings <-chan []store.Eve
 		timeoutContext, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
-		err := s.Store.EventTeamsUpsert(timeoutContext, rankingGroup)
-		if err != nil {
-			s.Logger.WithError(err).Errorf("unable to upsert rankings")
-			return
+		if err := s.updateRankings(timeoutContext, rankingGroup); err != nil {
+			s.Logger.WithError(err).Error("unable to store rankings")
 		}
-
-		s.Logger.WithField("count", len(rankingGroup)).Info("stored rankings")
 	}
 
 	for rankingGroup := range rankings {
 		storeRankings(rankingGroup)
 	}
 }
+
+func (s *Service) updateRankings(ctx context.Context, rankingGroup []store.EventTeam) error {
+	err := s.Store.EventTeamsUpsert(ctx, rankingGroup)
+	if err != nil {
+		return fmt.Errorf("unable to upsert rankings: %w", err)
+	}
+
+	s.Logger.WithField("count", len(rankingGroup)).Info("stored rankings")
+
+	if len(rankingGroup) > 0 {
+		s.Broker.Publish(notify.Message{Type: notify.TypeRankings, EventKey: rankingGroup[0].EventKey})
+	}
+
+	return nil
+}
+
+func (s *Service) fetchAlliances(ctx context.Context, eventKeys <-chan string, alliances chan<- store.AllianceSelection) {
+	const timeout = time.Second * 10
+
+	defer func() {
+		close(alliances)
+	}()
+
+	getAlliances := func(eventKey string) {
+		timeoutContext, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		selection, err := s.TBA.GetAlliances(timeoutContext, eventKey)
+		if errors.Is(err, tba.ErrNotModified{}) {
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Errorf("unable get alliances from TBA for event %q", eventKey)
+			return
+		}
+
+		if len(selection.Alliances) == 0 {
+			return
+		}
+
+		alliances <- selection
+
+		s.Logger.WithField("count", len(selection.Alliances)).Info("sent alliances")
+	}
+
+	for eventKey := range eventKeys {
+		getAlliances(eventKey)
+	}
+}
+
+// storeAlliances reconciles stored alliance selections with TBA. Once TBA publishes
+// alliances for an event they replace the alliances every realm tracked, keeping the
+// teams each realm recorded as having declined.
+func (s *Service) storeAlliances(ctx context.Context, alliances <-chan store.AllianceSelection) {
+	const timeout = time.Second * 10
+
+	upsertAlliances := func(selection store.AllianceSelection) {
+		timeoutContext, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		if err := s.updateAlliances(timeoutContext, selection); err != nil {
+			s.Logger.WithError(err).Error("unable to store alliances")
+		}
+	}
+
+	for selection := range alliances {
+		upsertAlliances(selection)
+	}
+}
+
+func (s *Service) updateAlliances(ctx context.Context, selection store.AllianceSelection) error {
+	err := s.Store.AllianceSelectionsUpsert(ctx, []store.AllianceSelection{selection})
+	if err != nil {
+		return fmt.Errorf("unable to upsert alliances: %w", err)
+	}
+
+	s.Logger.WithField("eventKey", selection.EventKey).Info("stored alliances")
+
+	return nil
+}
+
+// updateScoutSchedules remakes the scout assignments for the unplayed matches of an event
+// of any realm whose assignments were made from a different match schedule.
+func (s *Service) updateScoutSchedules(ctx context.Context, eventKey string) {
+	schedules, err := s.Store.GetScoutSchedulesForEvent(ctx, eventKey)
+	if err != nil {
+		s.Logger.WithError(err).Errorf("unable to retrieve scout schedules")
+		return
+	}
+
+	for _, schedule := range schedules {
+		matches, err := s.Store.GetMatchesForRealm(ctx, eventKey, nil, false, &schedule.RealmID)
+		if err != nil {
+			s.Logger.WithError(err).Errorf("unable to retrieve matches")
+			return
+		}
+
+		fingerprint := assignment.Fingerprint(matches)
+		if fingerprint == schedule.Fingerprint {
+			continue
+		}
+
+		users, err := s.Store.GetUsersByRealm(ctx, schedule.RealmID)
+		if err != nil {
+			s.Logger.WithError(err).Errorf("unable to retrieve realm users")
+			return
+		}
+
+		existing, err := s.Store.GetScoutAssignments(ctx, eventKey, schedule.RealmID, nil)
+		if err != nil {
+			s.Logger.WithError(err).Errorf("unable to retrieve scout assignments")
+			return
+		}
+
+		schedule.Fingerprint = fingerprint
+		assignments := assignment.Schedule(matches, assignment.Scouts(users), schedule.Options, existing)
+
+		if err := s.Store.SetScoutSchedule(ctx, schedule, assignments); err != nil {
+			s.Logger.WithError(err).Errorf("unable to store scout schedule")
+			return
+		}
+
+		s.Logger.WithField("eventKey", eventKey).WithField("realmId", schedule.RealmID).Info("updated scout assignments")
+	}
+}
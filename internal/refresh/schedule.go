@@ -0,0 +1,254 @@
+package refresh
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+)
+
+const (
+	// matchWindow is the least time before the next match's predicted time that an event is
+	// polled at the polling interval, when the active event buffer is shorter.
+	matchWindow = time.Minute * 10
+	// overdueLimit is how long after its predicted time an unplayed match stops being
+	// waited on, so that a match TBA never marks as played doesn't keep its event busy.
+	overdueLimit = time.Hour
+	// backoffInterval is the longest wait during an event between matches, such as during
+	// lunch, overnight, or before playoff matches are scheduled.
+	backoffInterval = time.Minute * 30
+	// waitingInterval is the wait during an event before its match schedule is known.
+	waitingInterval = time.Minute * 5
+	// idleInterval is the wait for events that aren't active.
+	idleInterval = time.Hour * 6
+)
+
+// nextPoll returns when an event's matches should next be polled after now, from the
+// event's stored matches and whether it is active. Active events are polled every interval
+// from buffer (but at least matchWindow) before the predicted time of their next unplayed
+// match, and after their last played match for as long as they stay active. Between
+// matches, polling backs off until then, but no longer than backoffInterval. Events that
+// aren't active are polled every idleInterval.
+func nextPoll(now time.Time, active bool, matches []store.Match, interval, buffer time.Duration) time.Time {
+	if !active {
+		return now.Add(idleInterval)
+	}
+
+	lead := buffer
+	if lead < matchWindow {
+		lead = matchWindow
+	}
+
+	var nextMatch, lastPlayed *time.Time
+	for i := range matches {
+		match := &matches[i]
+
+		if match.ActualTime != nil {
+			if lastPlayed == nil || match.ActualTime.After(*lastPlayed) {
+				lastPlayed = match.ActualTime
+			}
+			continue
+		}
+		if match.RedScore != nil || match.BlueScore != nil {
+			continue
+		}
+
+		t := match.PredictedTime
+		if t == nil {
+			t = match.ScheduledTime
+		}
+		if t == nil || now.After(t.Add(overdueLimit)) {
+			continue
+		}
+
+		if nextMatch == nil || t.Before(*nextMatch) {
+			nextMatch = t
+		}
+	}
+
+	switch {
+	case nextMatch != nil && !now.Before(nextMatch.Add(-lead)):
+		return now.Add(interval)
+	case nextMatch != nil:
+		return earliest(nextMatch.Add(-lead), now.Add(backoffInterval))
+	case lastPlayed != nil:
+		return now.Add(interval)
+	default:
+		return now.Add(waitingInterval)
+	}
+}
+
+func earliest(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// NextPolls returns when each event's matches, rankings, and alliances are next planned to
+// be polled, by event key.
+func (s *Service) NextPolls() map[string]time.Time {
+	s.pollsMu.Lock()
+	defer s.pollsMu.Unlock()
+
+	polls := make(map[string]time.Time, len(s.nextPolls))
+	for eventKey, next := range s.nextPolls {
+		polls[eventKey] = next
+	}
+
+	return polls
+}
+
+// scheduleEventGroup replaces the events that are polled. New events are polled right away.
+func (s *Service) scheduleEventGroup(eventGroup []store.Event) {
+	s.pollsMu.Lock()
+	defer s.pollsMu.Unlock()
+
+	events := make(map[string]store.Event, len(eventGroup))
+	nextPolls := make(map[string]time.Time, len(eventGroup))
+	for _, event := range eventGroup {
+		events[event.Key] = event
+		nextPolls[event.Key] = s.nextPolls[event.Key]
+	}
+
+	s.pollEvents = events
+	s.nextPolls = nextPolls
+}
+
+// updateActiveEvents stores which events are active. Events that just became active are
+// due to be polled right away.
+func (s *Service) updateActiveEvents(ctx context.Context) {
+	activeEvents, err := s.Store.GetActiveEvents(ctx, s.ActiveEventBuffer)
+	if err != nil {
+		s.Logger.WithError(err).Error("unable to get active events")
+		return
+	}
+
+	s.pollsMu.Lock()
+	defer s.pollsMu.Unlock()
+
+	active := make(map[string]bool, len(activeEvents))
+	for _, eventKey := range activeEvents {
+		active[eventKey] = true
+
+		if _, ok := s.nextPolls[eventKey]; ok && !s.activeEvents[eventKey] {
+			s.nextPolls[eventKey] = time.Time{}
+		}
+	}
+
+	s.activeEvents = active
+}
+
+// dueEvents returns the events whose next poll is at or before now, soonest first.
+func (s *Service) dueEvents(now time.Time) []store.Event {
+	s.pollsMu.Lock()
+	defer s.pollsMu.Unlock()
+
+	var due []store.Event
+	for eventKey, next := range s.nextPolls {
+		if !next.After(now) {
+			due = append(due, s.pollEvents[eventKey])
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool { return s.nextPolls[due[i].Key].Before(s.nextPolls[due[j].Key]) })
+
+	return due
+}
+
+// planPoll sets when an event is next polled from its stored matches and whether it is
+// active, no sooner than TBA's cached matches for the event expire. It returns whether the
+// event is active.
+func (s *Service) planPoll(ctx context.Context, event store.Event, now time.Time) bool {
+	interval := pollInterval
+	if s.Webhooks {
+		interval = webhookPollInterval
+	}
+
+	s.pollsMu.Lock()
+	active := s.activeEvents[event.Key]
+	s.pollsMu.Unlock()
+
+	var next time.Time
+	matches, err := s.Store.GetMatchesForRealm(ctx, event.Key, nil, false, nil)
+	if err != nil {
+		s.Logger.WithError(err).Errorf("unable to retrieve matches for event %q", event.Key)
+		next = now.Add(waitingInterval)
+	} else {
+		next = nextPoll(now, active, matches, interval, s.ActiveEventBuffer)
+	}
+
+	if expiry := s.TBA.MatchesExpiry(event.Key); expiry.After(next) {
+		next = expiry
+	}
+
+	s.pollsMu.Lock()
+	defer s.pollsMu.Unlock()
+
+	if _, ok := s.nextPolls[event.Key]; ok {
+		s.nextPolls[event.Key] = next
+	}
+
+	return active
+}
+
+// scheduledPoll is an event whose poll is due. Only active events have their rankings and
+// alliances polled, since they don't change otherwise.
+type scheduledPoll struct {
+	eventKey string
+	active   bool
+}
+
+// scheduleEvents sends each event to be polled when its next poll is due, checking for due
+// events every tick and for active events every activeInterval.
+func (s *Service) scheduleEvents(ctx context.Context, tick, activeInterval time.Duration, polls chan<- scheduledPoll) {
+	const timeout = time.Second * 10
+
+	ticker := time.NewTicker(tick)
+	activeTicker := time.NewTicker(activeInterval)
+
+	defer func() {
+		ticker.Stop()
+		activeTicker.Stop()
+		close(polls)
+	}()
+
+	updateActiveEvents := func() {
+		timeoutContext, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		s.updateActiveEvents(timeoutContext)
+	}
+
+	sendEvent := func(event store.Event) {
+		timeoutContext, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		active := s.planPoll(timeoutContext, event, time.Now())
+
+		select {
+		case polls <- scheduledPoll{eventKey: event.Key, active: active}:
+		case <-ctx.Done():
+		}
+	}
+
+	updateActiveEvents()
+	for {
+		select {
+		case <-activeTicker.C:
+			updateActiveEvents()
+		case <-ticker.C:
+			due := s.dueEvents(time.Now())
+			for _, event := range due {
+				sendEvent(event)
+			}
+
+			if len(due) > 0 {
+				s.Logger.WithField("count", len(due)).Info("sent scheduled events")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
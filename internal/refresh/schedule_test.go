@@ -0,0 +1,143 @@
+package refresh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+)
+
+func newTime(t time.Time) *time.Time {
+	return &t
+}
+
+func newInt(i int) *int {
+	return &i
+}
+
+func TestNextPoll(t *testing.T) {
+	const (
+		interval = time.Second * 30
+		buffer   = time.Hour
+	)
+
+	now := time.Date(2019, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		now      time.Time
+		active   bool
+		matches  []store.Match
+		buffer   time.Duration
+		expected time.Time
+	}{
+		{
+			name: "not active",
+			now:  now,
+			matches: []store.Match{
+				{Key: "qm1", ScheduledTime: newTime(now.Add(time.Minute * 5))},
+			},
+			expected: now.Add(idleInterval),
+		},
+		{
+			name:     "no match schedule",
+			now:      now,
+			active:   true,
+			expected: now.Add(waitingInterval),
+		},
+		{
+			name:   "next match soon",
+			now:    now,
+			active: true,
+			matches: []store.Match{
+				{Key: "qm1", ActualTime: newTime(now.Add(-time.Minute * 7)), RedScore: newInt(50)},
+				{Key: "qm3", ScheduledTime: newTime(now.Add(time.Minute * 20)), PredictedTime: newTime(now.Add(time.Minute * 14))},
+				{Key: "qm2", ScheduledTime: newTime(now.Add(time.Minute * 10)), PredictedTime: newTime(now.Add(time.Minute * 5))},
+			},
+			expected: now.Add(interval),
+		},
+		{
+			name:   "next match running late",
+			now:    now,
+			active: true,
+			matches: []store.Match{
+				{Key: "qm2", PredictedTime: newTime(now.Add(-time.Minute * 10))},
+			},
+			expected: now.Add(interval),
+		},
+		{
+			name:   "next match within buffer",
+			now:    now,
+			active: true,
+			matches: []store.Match{
+				{Key: "qm40", ActualTime: newTime(now.Add(-time.Minute * 5)), RedScore: newInt(50)},
+				{Key: "qm41", ScheduledTime: newTime(now.Add(time.Minute * 50))},
+			},
+			expected: now.Add(interval),
+		},
+		{
+			name:   "lunch",
+			now:    now,
+			active: true,
+			matches: []store.Match{
+				{Key: "qm40", ActualTime: newTime(now.Add(-time.Minute * 5)), RedScore: newInt(50)},
+				{Key: "qm41", ScheduledTime: newTime(now.Add(time.Minute * 80))},
+			},
+			expected: now.Add(time.Minute * 20),
+		},
+		{
+			name:   "buffer shorter than match window",
+			now:    now,
+			active: true,
+			matches: []store.Match{
+				{Key: "qm40", ActualTime: newTime(now.Add(-time.Minute * 5)), RedScore: newInt(50)},
+				{Key: "qm41", ScheduledTime: newTime(now.Add(time.Minute * 8))},
+			},
+			buffer:   time.Minute * 5,
+			expected: now.Add(interval),
+		},
+		{
+			name:   "lunch with buffer shorter than match window",
+			now:    now,
+			active: true,
+			matches: []store.Match{
+				{Key: "qm40", ActualTime: newTime(now.Add(-time.Minute * 20)), RedScore: newInt(50)},
+				{Key: "qm41", ScheduledTime: newTime(now.Add(time.Minute * 25))},
+			},
+			buffer:   time.Minute * 5,
+			expected: now.Add(time.Minute * 15),
+		},
+		{
+			name:   "long break",
+			now:    now,
+			active: true,
+			matches: []store.Match{
+				{Key: "qm80", ActualTime: newTime(now.Add(-time.Hour * 3)), RedScore: newInt(50)},
+				{Key: "qm81", ScheduledTime: newTime(now.Add(time.Hour * 15))},
+			},
+			expected: now.Add(backoffInterval),
+		},
+		{
+			name:   "after last match while active",
+			now:    now,
+			active: true,
+			matches: []store.Match{
+				{Key: "qm80", ActualTime: newTime(now.Add(-time.Minute * 20)), RedScore: newInt(50)},
+			},
+			expected: now.Add(interval),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.buffer == 0 {
+				tt.buffer = buffer
+			}
+
+			next := nextPoll(tt.now, tt.active, tt.matches, interval, tt.buffer)
+			if !next.Equal(tt.expected) {
+				t.Errorf("expected next poll at %v, but got %v", tt.expected, next)
+			}
+		})
+	}
+}
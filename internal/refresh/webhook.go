@@ -0,0 +1,82 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/npmanos/4176Gameday-backend/internal/notify"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/tba"
+)
+
+// RefreshMatches fetches an event's matches from TBA and stores them the same way polling
+// does. It is used when TBA notifies a webhook that the event's schedule changed.
+func (s *Service) RefreshMatches(ctx context.Context, eventKey string) error {
+	tbaMatches, err := s.TBA.GetMatches(ctx, eventKey)
+	if errors.Is(err, tba.ErrNotModified{}) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to get matches from TBA for event %q: %w", eventKey, err)
+	}
+
+	return s.updateMatches(ctx, eventMatches{EventKey: eventKey, Matches: tbaMatches})
+}
+
+// RefreshAlliances fetches an event's alliances from TBA and stores them the same way
+// polling does. It is used when TBA notifies a webhook of alliance selection.
+func (s *Service) RefreshAlliances(ctx context.Context, eventKey string) error {
+	selection, err := s.TBA.GetAlliances(ctx, eventKey)
+	if errors.Is(err, tba.ErrNotModified{}) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to get alliances from TBA for event %q: %w", eventKey, err)
+	}
+
+	if len(selection.Alliances) == 0 {
+		return nil
+	}
+
+	return s.updateAlliances(ctx, selection)
+}
+
+// UpdateMatch stores a single match sent to a webhook by TBA, such as a newly scored match,
+// then updates what depends on it. Unlike polling, other matches of the event are left as
+// they are.
+func (s *Service) UpdateMatch(ctx context.Context, match store.Match) error {
+	scoresChanged, err := s.Store.UpdateTBAMatches(ctx, []store.Match{match})
+	if err != nil {
+		return fmt.Errorf("unable to upsert match: %w", err)
+	}
+
+	s.Logger.WithField("eventKey", match.EventKey).WithField("matchKey", match.Key).Info("stored match")
+
+	s.Broker.Publish(notify.Message{Type: notify.TypeMatches, EventKey: match.EventKey})
+
+	if scoresChanged {
+		matches, err := s.Store.GetMatchesForRealm(ctx, match.EventKey, nil, false, nil)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve matches: %w", err)
+		}
+
+		s.updateOPRs(ctx, eventMatches{EventKey: match.EventKey, Matches: matches})
+	}
+
+	s.updateScoutSchedules(ctx, match.EventKey)
+
+	return nil
+}
+
+// RefreshRankings fetches an event's rankings from TBA and stores them the same way polling
+// does. TBA doesn't notify webhooks of ranking changes, so it is used when a match is
+// scored instead.
+func (s *Service) RefreshRankings(ctx context.Context, eventKey string) error {
+	rankings, err := s.TBA.GetTeamRankings(ctx, eventKey)
+	if errors.Is(err, tba.ErrNotModified{}) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to get rankings from TBA for event %q: %w", eventKey, err)
+	}
+
+	return s.updateRankings(ctx, rankings)
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/gorilla/mux"
+)
+
+type allianceSelection struct {
+	store.AllianceSelection
+	NextAlliance *int             `json:"nextAlliance"`
+	Round        int              `json:"round"`
+	PickLists    []store.PickList `json:"pickLists"`
+}
+
+// newAllianceSelection adds the next alliance to pick and the given pick lists, with
+// teams that have already been picked removed, to an alliance selection.
+func newAllianceSelection(selection store.AllianceSelection, pickLists []store.PickList) allianceSelection {
+	res := allianceSelection{
+		AllianceSelection: selection,
+		PickLists:         make([]store.PickList, 0, len(pickLists)),
+	}
+
+	if alliance, round, done := selection.Turn(); !done {
+		res.NextAlliance = &alliance
+		res.Round = round
+	}
+
+	picked := selection.Picked()
+	for _, pickList := range pickLists {
+		teams := make(store.PickListTeams, 0, len(pickList.Teams))
+		for _, team := range pickList.Teams {
+			if !picked[team.Team] {
+				teams = append(teams, team)
+			}
+		}
+
+		pickList.Teams = teams
+		res.PickLists = append(res.PickLists, pickList)
+	}
+
+	return res
+}
+
+type allianceSelectionAction struct {
+	Type     string `json:"type"`
+	Team     string `json:"team"`
+	Alliance int    `json:"alliance"`
+}
+
+// allianceSelectionHandler returns a handler to get the user's realm's alliance selection
+// state for an event along with the user's realm's pick lists with picked teams removed.
+func (s *Server) allianceSelectionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		selection, err := s.Store.GetAllianceSelection(r.Context(), eventKey, realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			selection = store.NewAllianceSelection(eventKey)
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving alliance selection")
+			return
+		}
+
+		pickLists, err := s.Store.GetPickListsForRealm(r.Context(), eventKey, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving pick lists")
+			return
+		}
+
+		ihttp.Respond(w, newAllianceSelection(selection, pickLists), http.StatusOK)
+	}
+}
+
+// allianceSelectionActionHandler returns a handler to set a captain, pick a team, or
+// record a declined invitation during alliance selection.
+func (s *Server) allianceSelectionActionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		var action allianceSelectionAction
+		if err := json.NewDecoder(r.Body).Decode(&action); err != nil || action.Team == "" {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if action.Type != "captain" && action.Type != "pick" && action.Type != "decline" {
+			ihttp.Respond(w, errors.New("action type must be one of captain, pick, or decline"), http.StatusUnprocessableEntity)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		selection, err := s.Store.EditAllianceSelection(r.Context(), eventKey, realmID, func(selection *store.AllianceSelection) error {
+			switch action.Type {
+			case "captain":
+				return selection.SetCaptain(action.Alliance, action.Team)
+			case "pick":
+				return selection.Pick(action.Team)
+			default:
+				return selection.Decline(action.Team)
+			}
+		})
+		var invalidSelection store.ErrInvalidSelection
+		if errors.As(err, &invalidSelection) {
+			ihttp.Respond(w, invalidSelection, http.StatusUnprocessableEntity)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("editing alliance selection")
+			return
+		}
+
+		pickLists, err := s.Store.GetPickListsForRealm(r.Context(), eventKey, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving pick lists")
+			return
+		}
+
+		ihttp.Respond(w, newAllianceSelection(selection, pickLists), http.StatusOK)
+	}
+}
+
+// resetAllianceSelectionHandler returns a handler to clear the user's realm's alliance
+// selection state for an event.
+func (s *Server) resetAllianceSelectionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		err = s.Store.DeleteAllianceSelection(r.Context(), eventKey, realmID)
+		if err != nil && !errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("deleting alliance selection")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
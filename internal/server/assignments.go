@@ -0,0 +1,161 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/npmanos/4176Gameday-backend/internal/assignment"
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/gorilla/mux"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type scoutAssignments struct {
+	Options     store.ScoutScheduleOptions `json:"options"`
+	Assignments []store.ScoutAssignment    `json:"assignments"`
+}
+
+// getAssignmentsHandler returns a handler to get the user's realm's scout assignments for
+// an event, optionally only for a single user.
+func (s *Server) getAssignmentsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		var userID *int64
+		if rawUserID := r.URL.Query().Get("userId"); rawUserID != "" {
+			id, err := strconv.ParseInt(rawUserID, 10, 64)
+			if err != nil {
+				ihttp.Error(w, http.StatusBadRequest)
+				return
+			}
+			userID = &id
+		}
+
+		schedule, err := s.Store.GetScoutSchedule(r.Context(), eventKey, realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving scout schedule")
+			return
+		}
+
+		assignments, err := s.Store.GetScoutAssignments(r.Context(), eventKey, realmID, userID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving scout assignments")
+			return
+		}
+
+		ihttp.Respond(w, scoutAssignments{Options: schedule.Options, Assignments: assignments}, http.StatusOK)
+	}
+}
+
+// scheduleAssignmentsHandler returns a handler to assign the user's realm's verified users
+// to scout the unplayed qualification matches of an event, replacing their existing
+// assignments. Played matches keep theirs. The assignments of unplayed matches are remade
+// with the same options whenever TBA changes the schedule.
+func (s *Server) scheduleAssignmentsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		var opts store.ScoutScheduleOptions
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err := validator.New().Struct(opts); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		matches, err := s.Store.GetMatchesForRealm(r.Context(), eventKey, nil, false, &realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving matches")
+			return
+		}
+
+		users, err := s.Store.GetUsersByRealm(r.Context(), realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving realm users")
+			return
+		}
+
+		existing, err := s.Store.GetScoutAssignments(r.Context(), eventKey, realmID, nil)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving scout assignments")
+			return
+		}
+
+		schedule := store.ScoutSchedule{
+			RealmID:     realmID,
+			EventKey:    eventKey,
+			Options:     opts,
+			Fingerprint: assignment.Fingerprint(matches),
+		}
+		assignments := assignment.Schedule(matches, assignment.Scouts(users), opts, existing)
+
+		if err := s.Store.SetScoutSchedule(r.Context(), schedule, assignments); err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("storing scout schedule")
+			return
+		}
+
+		ihttp.Respond(w, scoutAssignments{Options: opts, Assignments: assignments}, http.StatusOK)
+	}
+}
+
+// deleteAssignmentsHandler returns a handler to delete the user's realm's scout
+// assignments for an event.
+func (s *Server) deleteAssignmentsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		err = s.Store.DeleteScoutSchedule(r.Context(), eventKey, realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("deleting scout schedule")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/gorilla/mux"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type commentRequest struct {
+	Comment string `json:"comment" validate:"required"`
+}
+
+// commentFilter reads the reporterId and q (text search) query parameters of a request.
+func commentFilter(r *http.Request) (store.CommentFilter, error) {
+	filter := store.CommentFilter{Search: r.URL.Query().Get("q")}
+
+	if reporter := r.URL.Query().Get("reporterId"); reporter != "" {
+		reporterID, err := strconv.ParseInt(reporter, 10, 64)
+		if err != nil {
+			return filter, err
+		}
+		filter.ReporterID = &reporterID
+	}
+
+	return filter, nil
+}
+
+// getCommentsHandler returns a handler to get every comment about a team at an event,
+// including report comments, from the user's realm and realms that share reports. If the
+// route has a match key, only comments about that match are returned.
+func (s *Server) getCommentsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey, teamKey := vars["eventKey"], vars["teamKey"]
+
+		filter, err := commentFilter(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		if matchKey, ok := vars["matchKey"]; ok {
+			filter.MatchKey = &matchKey
+		}
+
+		var realmID *int64
+		userRealmID, err := ihttp.GetRealmID(r)
+		if err == nil {
+			realmID = &userRealmID
+		}
+
+		comments, err := s.Store.GetEventTeamCommentsForRealm(r.Context(), eventKey, teamKey, filter, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("getting comments")
+			return
+		}
+
+		ihttp.Respond(w, comments, http.StatusOK)
+	}
+}
+
+// createCommentHandler returns a handler to write a comment about a team at an event. If
+// the route has a match key, the comment is about that match.
+func (s *Server) createCommentHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey, teamKey := vars["eventKey"], vars["teamKey"]
+
+		var req commentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		reporterID, err := ihttp.GetSubject(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		comment := store.Comment{
+			EventKey:   eventKey,
+			TeamKey:    teamKey,
+			ReporterID: &reporterID,
+			RealmID:    &realmID,
+			Comment:    req.Comment,
+		}
+
+		if matchKey, ok := vars["matchKey"]; ok {
+			comment.MatchKey = &matchKey
+		}
+
+		id, err := s.Store.CreateComment(r.Context(), comment)
+		if errors.Is(err, store.ErrFKeyViolation{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("creating comment")
+			return
+		}
+		comment.ID = &id
+
+		ihttp.Respond(w, comment, http.StatusCreated)
+	}
+}
+
+// updateCommentHandler returns a handler to replace the text of one of the user's comments.
+// If the route has a match key, the comment must be about that match.
+func (s *Server) updateCommentHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey, teamKey := vars["eventKey"], vars["teamKey"]
+
+		id, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		var req commentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		reporterID, err := ihttp.GetSubject(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		var matchKey *string
+		if key, ok := vars["matchKey"]; ok {
+			matchKey = &key
+		}
+
+		err = s.Store.UpdateComment(r.Context(), eventKey, matchKey, teamKey, id, reporterID, req.Comment)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("updating comment")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// deleteCommentHandler returns a handler to delete one of the user's comments. If the route
+// has a match key, the comment must be about that match.
+func (s *Server) deleteCommentHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey, teamKey := vars["eventKey"], vars["teamKey"]
+
+		id, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		reporterID, err := ihttp.GetSubject(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		var matchKey *string
+		if key, ok := vars["matchKey"]; ok {
+			matchKey = &key
+		}
+
+		err = s.Store.DeleteComment(r.Context(), eventKey, matchKey, teamKey, id, reporterID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("deleting comment")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
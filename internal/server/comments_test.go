@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCommentFilter(t *testing.T) {
+	reporterID := int64(7)
+
+	testCases := []struct {
+		name      string
+		query     string
+		expected  store.CommentFilter
+		expectErr bool
+	}{
+		{
+			name:     "none",
+			expected: store.CommentFilter{},
+		},
+		{
+			name:     "reporter and search",
+			query:    "?reporterId=7&q=good%20defense",
+			expected: store.CommentFilter{ReporterID: &reporterID, Search: "good defense"},
+		},
+		{
+			name:      "invalid reporter",
+			query:     "?reporterId=abc",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/events/2019flor/teams/frc4176/comments"+tt.query, nil)
+
+			filter, err := commentFilter(r)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("expected error to be %t, but got: %v", tt.expectErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if !cmp.Equal(tt.expected, filter) {
+				t.Errorf("expected filter to equal expected, but got diff: %s", cmp.Diff(tt.expected, filter))
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/gorilla/mux"
+)
+
+type slotCoverage struct {
+	Team     string `json:"team"`
+	Alliance string `json:"alliance"`
+	Reports  int    `json:"reports"`
+}
+
+type matchCoverage struct {
+	Key   string         `json:"key"`
+	Slots []slotCoverage `json:"slots"`
+}
+
+type teamCoverage struct {
+	Team     string  `json:"team"`
+	Played   int     `json:"played"`
+	Scouted  int     `json:"scouted"`
+	Coverage float64 `json:"coverage"`
+}
+
+type skippedAssignment struct {
+	MatchKey string `json:"matchKey"`
+	TeamKey  string `json:"teamKey"`
+}
+
+type reporterSkips struct {
+	ReporterID int64               `json:"reporterId"`
+	Skipped    []skippedAssignment `json:"skipped"`
+}
+
+type eventCoverage struct {
+	Matches   []matchCoverage `json:"matches"`
+	Teams     []teamCoverage  `json:"teams"`
+	Reporters []reporterSkips `json:"reporters"`
+}
+
+// calculateCoverage counts the reports for every team in each played match, the percent
+// of each team's played matches that have at least one report, and the scout assignments
+// in played matches that the assigned reporter didn't report on. Matches are ordered by
+// time, teams by coverage then key, and reporters by ID.
+func calculateCoverage(matches []store.Match, reports []store.Report, assignments []store.ScoutAssignment) eventCoverage {
+	type slot struct{ match, team string }
+
+	reportCounts := make(map[slot]int)
+	reported := make(map[slot]map[int64]bool)
+	for _, report := range reports {
+		key := slot{report.MatchKey, report.TeamKey}
+		reportCounts[key]++
+
+		if report.ReporterID != nil {
+			if reported[key] == nil {
+				reported[key] = make(map[int64]bool)
+			}
+			reported[key][*report.ReporterID] = true
+		}
+	}
+
+	played := make([]store.Match, 0, len(matches))
+	for _, match := range matches {
+		if match.RedScore != nil && match.BlueScore != nil && !match.TBADeleted {
+			played = append(played, match)
+		}
+	}
+
+	sortMatchesByTime(played)
+
+	coverage := eventCoverage{
+		Matches:   make([]matchCoverage, 0, len(played)),
+		Teams:     make([]teamCoverage, 0),
+		Reporters: make([]reporterSkips, 0),
+	}
+
+	teams := make(map[string]*teamCoverage)
+	playedMatches := make(map[string]bool)
+
+	for _, match := range played {
+		playedMatches[match.Key] = true
+		mc := matchCoverage{Key: match.Key, Slots: make([]slotCoverage, 0, len(match.RedAlliance)+len(match.BlueAlliance))}
+
+		addSlots := func(alliance string, teamKeys []string) {
+			for _, team := range teamKeys {
+				count := reportCounts[slot{match.Key, team}]
+				mc.Slots = append(mc.Slots, slotCoverage{Team: team, Alliance: alliance, Reports: count})
+
+				if teams[team] == nil {
+					teams[team] = &teamCoverage{Team: team}
+				}
+				teams[team].Played++
+				if count > 0 {
+					teams[team].Scouted++
+				}
+			}
+		}
+		addSlots("red", match.RedAlliance)
+		addSlots("blue", match.BlueAlliance)
+
+		coverage.Matches = append(coverage.Matches, mc)
+	}
+
+	for _, team := range teams {
+		team.Coverage = float64(team.Scouted) / float64(team.Played) * 100
+		coverage.Teams = append(coverage.Teams, *team)
+	}
+
+	sort.Slice(coverage.Teams, func(i, j int) bool {
+		a, b := coverage.Teams[i], coverage.Teams[j]
+		if a.Coverage != b.Coverage {
+			return a.Coverage < b.Coverage
+		}
+		return a.Team < b.Team
+	})
+
+	skips := make(map[int64][]skippedAssignment)
+	for _, assignment := range assignments {
+		if !playedMatches[assignment.MatchKey] || reported[slot{assignment.MatchKey, assignment.TeamKey}][assignment.UserID] {
+			continue
+		}
+
+		skips[assignment.UserID] = append(skips[assignment.UserID], skippedAssignment{
+			MatchKey: assignment.MatchKey,
+			TeamKey:  assignment.TeamKey,
+		})
+	}
+
+	for reporterID, skipped := range skips {
+		coverage.Reporters = append(coverage.Reporters, reporterSkips{ReporterID: reporterID, Skipped: skipped})
+	}
+
+	sort.Slice(coverage.Reporters, func(i, j int) bool {
+		return coverage.Reporters[i].ReporterID < coverage.Reporters[j].ReporterID
+	})
+
+	return coverage
+}
+
+// eventCoverageHandler returns a handler to get how well the user's realm has scouted the
+// played matches of an event: the number of the realm's reports for each team in each
+// match, each team's coverage percentage, and the realm's reporters that skipped matches
+// they were assigned to scout.
+func (s *Server) eventCoverageHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		matches, err := s.Store.GetMatchesForRealm(r.Context(), eventKey, nil, false, &realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving matches")
+			return
+		}
+
+		eventReports, err := s.Store.GetEventReportsForRealm(r.Context(), eventKey, &realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving reports")
+			return
+		}
+
+		// Only the realm's own reports count towards its coverage, even if other realms
+		// share theirs.
+		reports := make([]store.Report, 0, len(eventReports))
+		for _, report := range eventReports {
+			if report.RealmID != nil && *report.RealmID == realmID {
+				reports = append(reports, report)
+			}
+		}
+
+		assignments, err := s.Store.GetScoutAssignments(r.Context(), eventKey, realmID, nil)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving scout assignments")
+			return
+		}
+
+		ihttp.Respond(w, calculateCoverage(matches, reports, assignments), http.StatusOK)
+	}
+}
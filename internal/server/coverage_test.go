@@ -0,0 +1,114 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCalculateCoverage(t *testing.T) {
+	score := 10
+	start := time.Date(2019, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+	match := func(key string, minutes int, played bool) store.Match {
+		scheduled := start.Add(time.Minute * time.Duration(minutes))
+		m := store.Match{
+			Key:           key,
+			ScheduledTime: &scheduled,
+			RedAlliance:   []string{"frc1", "frc2"},
+			BlueAlliance:  []string{"frc3", "frc4"},
+		}
+		if played {
+			m.RedScore, m.BlueScore = &score, &score
+		}
+		return m
+	}
+
+	report := func(matchKey, teamKey string, reporterID int64) store.Report {
+		return store.Report{MatchKey: matchKey, TeamKey: teamKey, ReporterID: &reporterID}
+	}
+
+	assign := func(matchKey, teamKey string, userID int64) store.ScoutAssignment {
+		return store.ScoutAssignment{MatchKey: matchKey, TeamKey: teamKey, UserID: userID}
+	}
+
+	slots := func(frc1, frc2, frc3, frc4 int) []slotCoverage {
+		return []slotCoverage{
+			{Team: "frc1", Alliance: "red", Reports: frc1},
+			{Team: "frc2", Alliance: "red", Reports: frc2},
+			{Team: "frc3", Alliance: "blue", Reports: frc3},
+			{Team: "frc4", Alliance: "blue", Reports: frc4},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		matches     []store.Match
+		reports     []store.Report
+		assignments []store.ScoutAssignment
+		expected    eventCoverage
+	}{
+		{
+			name: "no played matches",
+			matches: []store.Match{
+				match("qm1", 0, false),
+			},
+			reports: []store.Report{report("qm1", "frc1", 1)},
+			expected: eventCoverage{
+				Matches:   []matchCoverage{},
+				Teams:     []teamCoverage{},
+				Reporters: []reporterSkips{},
+			},
+		},
+		{
+			name: "counts reports and skipped assignments in played matches",
+			matches: []store.Match{
+				match("qm2", 7, true),
+				match("qm1", 0, true),
+				match("qm3", 14, false),
+			},
+			reports: []store.Report{
+				report("qm1", "frc1", 1),
+				report("qm1", "frc1", 2),
+				report("qm1", "frc2", 3),
+				report("qm2", "frc1", 1),
+				report("qm2", "frc3", 4),
+			},
+			assignments: []store.ScoutAssignment{
+				assign("qm1", "frc1", 1),
+				assign("qm1", "frc4", 2),
+				assign("qm2", "frc4", 2),
+				assign("qm2", "frc3", 3),
+				assign("qm3", "frc1", 1),
+			},
+			expected: eventCoverage{
+				Matches: []matchCoverage{
+					{Key: "qm1", Slots: slots(2, 1, 0, 0)},
+					{Key: "qm2", Slots: slots(1, 0, 1, 0)},
+				},
+				Teams: []teamCoverage{
+					{Team: "frc4", Played: 2, Scouted: 0, Coverage: 0},
+					{Team: "frc2", Played: 2, Scouted: 1, Coverage: 50},
+					{Team: "frc3", Played: 2, Scouted: 1, Coverage: 50},
+					{Team: "frc1", Played: 2, Scouted: 2, Coverage: 100},
+				},
+				Reporters: []reporterSkips{
+					{ReporterID: 2, Skipped: []skippedAssignment{{MatchKey: "qm1", TeamKey: "frc4"}, {MatchKey: "qm2", TeamKey: "frc4"}}},
+					{ReporterID: 3, Skipped: []skippedAssignment{{MatchKey: "qm2", TeamKey: "frc3"}}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			coverage := calculateCoverage(tt.matches, tt.reports, tt.assignments)
+
+			if !cmp.Equal(tt.expected, coverage) {
+				t.Errorf("expected coverage to equal expected, but got diff: %s", cmp.Diff(tt.expected, coverage))
+			}
+		})
+	}
+}
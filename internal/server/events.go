@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"errors"
 
@@ -101,6 +102,14 @@ func (s *Server) upsertEventHandler() http.HandlerFunc {
 		}
 		roles := ihttp.GetRoles(r)
 
+		if event.Timezone == "" {
+			event.Timezone = "UTC"
+		}
+		if !validTimezone(event.Timezone) {
+			ihttp.Respond(w, fmt.Errorf("unknown timezone %q", event.Timezone), http.StatusUnprocessableEntity)
+			return
+		}
+
 		event.Key = eventKey
 		event.RealmID = &creatorRealm
 
@@ -128,6 +137,17 @@ func (s *Server) upsertEventHandler() http.HandlerFunc {
 	}
 }
 
+// validTimezone returns whether tz is the name of an IANA timezone, such as
+// America/New_York. Local is rejected since it depends on the server.
+func validTimezone(tz string) bool {
+	if tz == "Local" {
+		return false
+	}
+
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
+
 func editEvent(ctx context.Context, sto *store.Service, roles store.Roles, userRealmID int64, eventKey string, editFunc func(tx *sqlx.Tx) error) (existed bool, err error) {
 	existed = true
 
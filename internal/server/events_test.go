@@ -0,0 +1,23 @@
+package server
+
+import "testing"
+
+func TestValidTimezone(t *testing.T) {
+	testCases := []struct {
+		tz       string
+		expected bool
+	}{
+		{tz: "UTC", expected: true},
+		{tz: "America/New_York", expected: true},
+		{tz: "Local"},
+		{tz: "America/Nowhere"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.tz, func(t *testing.T) {
+			if valid := validTimezone(tt.tz); valid != tt.expected {
+				t.Errorf("expected %q to be valid: %t, but got %t", tt.tz, tt.expected, valid)
+			}
+		})
+	}
+}
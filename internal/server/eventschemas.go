@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/gorilla/mux"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type eventSchemaRequest struct {
+	SchemaID int64 `json:"schemaId" validate:"required"`
+}
+
+// setEventSchemaHandler returns a handler to override the schema the user's realm uses for
+// an event, including TBA events. The schema must be visible to the realm, and replaces the
+// event's schema of the same kind (match or pit).
+func (s *Server) setEventSchemaHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		var req eventSchemaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		schema, err := s.Store.GetSchemaByID(r.Context(), req.SchemaID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("getting schema by id")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		visible, err := s.schemaVisibleToRealm(r.Context(), schema, realmID)
+		if err != nil {
+			s.Logger.WithError(err).Error("checking schema visibility")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		} else if !visible {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		err = s.Store.SetEventSchema(r.Context(), store.EventSchema{
+			RealmID:  realmID,
+			EventKey: eventKey,
+			Kind:     schema.Kind,
+			SchemaID: req.SchemaID,
+		})
+		if errors.Is(err, store.ErrFKeyViolation{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("setting event schema")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// deleteEventSchemaHandler returns a handler to remove the user's realm's schema override
+// for an event, of the kind in the kind query parameter (match by default).
+func (s *Server) deleteEventSchemaHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		kind := r.URL.Query().Get("kind")
+		if kind == "" {
+			kind = store.SchemaKindMatch
+		} else if kind != store.SchemaKindMatch && kind != store.SchemaKindPit {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		err = s.Store.DeleteEventSchema(r.Context(), eventKey, realmID, kind)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("deleting event schema")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/npmanos/4176Gameday-backend/internal/export"
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// writeExport writes a table as an attachment in the given format, named after the event
+// and the data exported.
+func (s *Server) writeExport(w http.ResponseWriter, format export.Format, eventKey, name string, table export.Table) {
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+eventKey+"-"+name+"."+string(format)+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	if err := export.Write(w, format, table); err != nil {
+		s.Logger.WithError(err).Error("writing export")
+	}
+}
+
+// exportReportsHandler returns a handler to export every report at an event visible to the
+// user's realm, ordered by when their matches were played, with a column for each visible
+// report field of the event's schema.
+func (s *Server) exportReportsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		format, err := export.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		var realmID *int64
+		userRealmID, err := ihttp.GetRealmID(r)
+		if err == nil {
+			realmID = &userRealmID
+		}
+
+		event, err := s.Store.GetEventForRealm(r.Context(), eventKey, realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		schema, err := s.eventReportSchema(r.Context(), event)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event schema")
+			return
+		}
+
+		reports, err := s.Store.GetEventReportsForRealm(r.Context(), eventKey, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving reports")
+			return
+		}
+
+		matches, err := s.Store.GetEventAnalysisInfoForRealm(r.Context(), eventKey, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving matches")
+			return
+		}
+
+		matchOrder := make(map[string]int)
+		for i, match := range matches {
+			matchOrder[match.Key] = i
+		}
+		sort.SliceStable(reports, func(i, j int) bool {
+			if a, b := matchOrder[reports[i].MatchKey], matchOrder[reports[j].MatchKey]; a != b {
+				return a < b
+			}
+			return reports[i].TeamKey < reports[j].TeamKey
+		})
+
+		table := export.Reports(schema, storeMigrationsToSummaryMigrations(schema), reports)
+		s.writeExport(w, format, eventKey, "reports", table)
+	}
+}
+
+// exportStatsHandler returns a handler to export the summary of every team at an event, the
+// same as eventStats, with columns for each visible field of the event's schema.
+func (s *Server) exportStatsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		format, err := export.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		var realmID *int64
+		userRealmID, err := ihttp.GetRealmID(r)
+		if err == nil {
+			realmID = &userRealmID
+		}
+
+		schema, summaries, err := s.summarizeEvent(r.Context(), eventKey, realmID, 0)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if errors.Is(err, errNoSchema) {
+			ihttp.Respond(w, errNoSchema, http.StatusBadRequest)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("summarizing event")
+			return
+		}
+
+		s.writeExport(w, format, eventKey, "stats", export.Summaries(schema.Schema, summaries))
+	}
+}
+
+// exportMatchesHandler returns a handler to export every match at an event with its score
+// breakdowns flattened into columns.
+func (s *Server) exportMatchesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		format, err := export.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		var realmID *int64
+		userRealmID, err := ihttp.GetRealmID(r)
+		if err == nil {
+			realmID = &userRealmID
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), eventKey, realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		matches, err := s.Store.GetMatchesForRealm(r.Context(), eventKey, nil, false, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving matches")
+			return
+		}
+		sortMatchesByTime(matches)
+
+		s.writeExport(w, format, eventKey, "matches", export.Matches(matches))
+	}
+}
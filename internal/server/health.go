@@ -27,12 +27,15 @@ type healthServices struct {
 }
 
 type healthStatus struct {
-	Uptime   string         `json:"uptime"`
-	Services healthServices `json:"services"`
-	Ok       bool           `json:"ok"`
+	Uptime    string               `json:"uptime"`
+	Services  healthServices       `json:"services"`
+	Ok        bool                 `json:"ok"`
+	NextPolls map[string]time.Time `json:"nextPolls"`
 }
 
-func healthHandler(getUptime func() time.Duration, tba, postgres Pinger) http.HandlerFunc {
+// healthHandler returns a handler for the health of the server and the services it uses,
+// and when each event is next planned to be polled from TBA.
+func healthHandler(getUptime func() time.Duration, tba, postgres Pinger, getNextPolls func() map[string]time.Time) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		services := healthServices{
 			TBA:        tba.Ping(r.Context()) == nil,
@@ -40,9 +43,10 @@ func healthHandler(getUptime func() time.Duration, tba, postgres Pinger) http.Ha
 		}
 
 		ihttp.Respond(w, healthStatus{
-			Uptime:   getUptime().String(),
-			Services: services,
-			Ok:       services.TBA && services.PostgreSQL,
+			Uptime:    getUptime().String(),
+			Services:  services,
+			Ok:        services.TBA && services.PostgreSQL,
+			NextPolls: getNextPolls(),
 		}, http.StatusOK)
 	}
 }
@@ -64,6 +64,7 @@ func TestHealthHandler(t *testing.T) {
 		tbaHealthy       bool
 		postgresHealthy  bool
 		uptime           func() time.Duration
+		nextPolls        map[string]time.Time
 		expectedResponse healthStatus
 	}{
 		{
@@ -77,7 +78,8 @@ func TestHealthHandler(t *testing.T) {
 					TBA:        true,
 					PostgreSQL: true,
 				},
-				Ok: true,
+				Ok:        true,
+				NextPolls: map[string]time.Time{},
 			},
 		},
 		{
@@ -91,7 +93,8 @@ func TestHealthHandler(t *testing.T) {
 					TBA:        false,
 					PostgreSQL: true,
 				},
-				Ok: false,
+				Ok:        false,
+				NextPolls: map[string]time.Time{},
 			},
 		},
 		{
@@ -105,7 +108,30 @@ func TestHealthHandler(t *testing.T) {
 					TBA:        false,
 					PostgreSQL: false,
 				},
-				Ok: false,
+				Ok:        false,
+				NextPolls: map[string]time.Time{},
+			},
+		},
+		{
+			name:            "events are scheduled",
+			tbaHealthy:      true,
+			postgresHealthy: true,
+			uptime:          func() time.Duration { return time.Minute },
+			nextPolls: map[string]time.Time{
+				"2019ilch": time.Date(2019, 3, 15, 17, 0, 30, 0, time.UTC),
+				"2019wimi": time.Date(2019, 3, 15, 23, 0, 0, 0, time.UTC),
+			},
+			expectedResponse: healthStatus{
+				Uptime: "1m0s",
+				Services: healthServices{
+					TBA:        true,
+					PostgreSQL: true,
+				},
+				Ok: true,
+				NextPolls: map[string]time.Time{
+					"2019ilch": time.Date(2019, 3, 15, 17, 0, 30, 0, time.UTC),
+					"2019wimi": time.Date(2019, 3, 15, 23, 0, 0, 0, time.UTC),
+				},
 			},
 		},
 	}
@@ -119,7 +145,12 @@ func TestHealthHandler(t *testing.T) {
 				t.FailNow()
 			}
 
-			handler := healthHandler(tt.uptime, mockPinger{tt.tbaHealthy}, mockPinger{tt.postgresHealthy})
+			nextPolls := tt.nextPolls
+			if nextPolls == nil {
+				nextPolls = map[string]time.Time{}
+			}
+
+			handler := healthHandler(tt.uptime, mockPinger{tt.tbaHealthy}, mockPinger{tt.postgresHealthy}, func() map[string]time.Time { return nextPolls })
 
 			handler(rr, req)
 
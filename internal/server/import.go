@@ -0,0 +1,74 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/importer"
+)
+
+// importReportsHandler returns a handler to import reports from a CSV file into the user's
+// realm as the user's reports. The multipart form holds the JSON column mapping as mapping
+// and the CSV file as reports. When any row is invalid nothing is imported, and the errors
+// of every row are returned. With ?dryRun=true the reports are validated but not stored.
+func (s *Server) importReportsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		mapping, err := importer.ParseMapping(strings.NewReader(r.FormValue("mapping")))
+		if err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		file, _, err := r.FormFile("reports")
+		if err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+		defer file.Close()
+
+		reporterID, err := ihttp.GetSubject(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+
+		result, err := importer.Import(r.Context(), s.Store, file, mapping, importer.Options{
+			RealmID:    realmID,
+			ReporterID: reporterID,
+			DryRun:     dryRun,
+		})
+		if errors.Is(err, importer.ErrInvalidCSV) {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("importing reports")
+			return
+		}
+
+		switch {
+		case len(result.Errors) != 0:
+			ihttp.Respond(w, result, http.StatusUnprocessableEntity)
+		case dryRun:
+			ihttp.Respond(w, result, http.StatusOK)
+		default:
+			ihttp.Respond(w, result, http.StatusCreated)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
@@ -29,6 +30,21 @@ type match struct {
 	Videos        []string   `json:"videos"`
 }
 
+// sortMatchesByTime sorts matches by their time, with matches without a time last and
+// matches with the same time by key.
+func sortMatchesByTime(matches []store.Match) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i].GetTime(), matches[j].GetTime()
+		if a != nil && b != nil && !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		if (a == nil) != (b == nil) {
+			return a != nil
+		}
+		return matches[i].Key < matches[j].Key
+	})
+}
+
 // matchesHandler returns a handler to get all matches at a given event.
 func (s *Server) matchesHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+	"github.com/gorilla/mux"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+// pickListWeight is how much a single summary stat average contributes to a team's
+// position when seeding a new pick list.
+type pickListWeight struct {
+	Name   string  `json:"name" validate:"required"`
+	Weight float64 `json:"weight"`
+}
+
+type createPickListRequest struct {
+	Name    string           `json:"name" validate:"gte=1,lte=64"`
+	Weights []pickListWeight `json:"weights" validate:"dive"`
+}
+
+// seedPickList orders the teams at an event by the weighted sum of their summary stat
+// averages. Ties (including teams with no summary) are broken by TBA rank, then team key.
+func seedPickList(teams []store.EventTeam, summaries map[string]summary.Summary, weights []pickListWeight) store.PickListTeams {
+	scores := make(map[string]float64)
+	for _, team := range teams {
+		for _, stat := range summaries[team.Key] {
+			for _, weight := range weights {
+				if weight.Name == stat.Name {
+					scores[team.Key] += weight.Weight * stat.Average
+				}
+			}
+		}
+	}
+
+	sorted := make([]store.EventTeam, len(teams))
+	copy(sorted, teams)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if scores[a.Key] != scores[b.Key] {
+			return scores[a.Key] > scores[b.Key]
+		}
+		if (a.Rank == nil) != (b.Rank == nil) {
+			return a.Rank != nil
+		}
+		if a.Rank != nil && *a.Rank != *b.Rank {
+			return *a.Rank < *b.Rank
+		}
+		return a.Key < b.Key
+	})
+
+	pickListTeams := make(store.PickListTeams, 0, len(sorted))
+	for _, team := range sorted {
+		pickListTeams = append(pickListTeams, store.PickListTeam{Team: team.Key})
+	}
+
+	return pickListTeams
+}
+
+// getPickListsHandler returns a handler to get all of the user's realm's pick lists for an event.
+func (s *Server) getPickListsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		pickLists, err := s.Store.GetPickListsForRealm(r.Context(), eventKey, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving pick lists")
+			return
+		}
+
+		ihttp.Respond(w, pickLists, http.StatusOK)
+	}
+}
+
+// createPickListHandler returns a handler to create a new pick list seeded from event stats.
+func (s *Server) createPickListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		var req createPickListRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		teams, err := s.Store.GetEventTeamsForRealm(r.Context(), eventKey, &realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event teams")
+			return
+		}
+
+		var summaries map[string]summary.Summary
+		if len(req.Weights) != 0 {
+			_, summaries, err = s.summarizeEvent(r.Context(), eventKey, &realmID, 0)
+			if errors.Is(err, errNoSchema) {
+				ihttp.Respond(w, errNoSchema, http.StatusBadRequest)
+				return
+			} else if err != nil {
+				ihttp.Error(w, http.StatusInternalServerError)
+				s.Logger.WithError(err).Error("summarizing event")
+				return
+			}
+		}
+
+		pickList := store.PickList{
+			EventKey: eventKey,
+			RealmID:  realmID,
+			Name:     req.Name,
+			Teams:    seedPickList(teams, summaries, req.Weights),
+		}
+
+		pickList.ID, err = s.Store.CreatePickList(r.Context(), pickList)
+		if errors.Is(err, store.ErrExists{}) {
+			ihttp.Error(w, http.StatusConflict)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("creating pick list")
+			return
+		}
+
+		ihttp.Respond(w, pickList, http.StatusCreated)
+	}
+}
+
+// getPickListHandler returns a handler to get a specific pick list.
+func (s *Server) getPickListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey := vars["eventKey"]
+
+		id, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		pickList, err := s.Store.GetPickListForRealm(r.Context(), eventKey, id, realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving pick list")
+			return
+		}
+
+		ihttp.Respond(w, pickList, http.StatusOK)
+	}
+}
+
+// updatePickListHandler returns a handler to rename, reorder, or flag teams on a pick list.
+func (s *Server) updatePickListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey := vars["eventKey"]
+
+		id, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		var pickList store.PickList
+		if err := json.NewDecoder(r.Body).Decode(&pickList); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err := validator.New().Struct(pickList); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		seen := make(map[string]bool)
+		for _, team := range pickList.Teams {
+			if seen[team.Team] {
+				ihttp.Respond(w, errors.New("pick list contains duplicate team "+team.Team), http.StatusUnprocessableEntity)
+				return
+			}
+			seen[team.Team] = true
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		pickList.ID = id
+		pickList.EventKey = eventKey
+		pickList.RealmID = realmID
+		if pickList.Teams == nil {
+			pickList.Teams = store.PickListTeams{}
+		}
+
+		err = s.Store.UpdatePickList(r.Context(), pickList)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if errors.Is(err, store.ErrExists{}) {
+			ihttp.Error(w, http.StatusConflict)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("updating pick list")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// deletePickListHandler returns a handler to delete a specific pick list.
+func (s *Server) deletePickListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey := vars["eventKey"]
+
+		id, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		err = s.Store.DeletePickList(r.Context(), eventKey, id, realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("deleting pick list")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newRank(rank int) *int {
+	return &rank
+}
+
+func TestSeedPickList(t *testing.T) {
+	teams := []store.EventTeam{
+		{Key: "frc1", Rank: newRank(3)},
+		{Key: "frc2", Rank: newRank(1)},
+		{Key: "frc3", Rank: newRank(2)},
+		{Key: "frc4"},
+		{Key: "frc5", Rank: newRank(4)},
+	}
+
+	summaries := map[string]summary.Summary{
+		"frc1": {
+			{FieldDescriptor: summary.FieldDescriptor{Name: "Cargo"}, Average: 4},
+			{FieldDescriptor: summary.FieldDescriptor{Name: "Hatches"}, Average: 1},
+		},
+		"frc2": {
+			{FieldDescriptor: summary.FieldDescriptor{Name: "Cargo"}, Average: 2},
+			{FieldDescriptor: summary.FieldDescriptor{Name: "Hatches"}, Average: 4},
+		},
+		"frc3": {
+			{FieldDescriptor: summary.FieldDescriptor{Name: "Cargo"}, Average: 1},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		weights  []pickListWeight
+		expected store.PickListTeams
+	}{
+		{
+			name:    "no weights orders by rank",
+			weights: nil,
+			expected: store.PickListTeams{
+				{Team: "frc2"}, {Team: "frc3"}, {Team: "frc1"}, {Team: "frc5"}, {Team: "frc4"},
+			},
+		},
+		{
+			name:    "single weight",
+			weights: []pickListWeight{{Name: "Cargo", Weight: 1}},
+			expected: store.PickListTeams{
+				{Team: "frc1"}, {Team: "frc2"}, {Team: "frc3"}, {Team: "frc5"}, {Team: "frc4"},
+			},
+		},
+		{
+			name:    "combined weights",
+			weights: []pickListWeight{{Name: "Cargo", Weight: 1}, {Name: "Hatches", Weight: 2}},
+			expected: store.PickListTeams{
+				{Team: "frc2"}, {Team: "frc1"}, {Team: "frc3"}, {Team: "frc5"}, {Team: "frc4"},
+			},
+		},
+		{
+			name:    "unknown stat is ignored",
+			weights: []pickListWeight{{Name: "Climbs", Weight: 5}},
+			expected: store.PickListTeams{
+				{Team: "frc2"}, {Team: "frc3"}, {Team: "frc1"}, {Team: "frc5"}, {Team: "frc4"},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := seedPickList(teams, summaries, tt.weights)
+
+			if !cmp.Equal(actual, tt.expected) {
+				t.Errorf("expected actual pick list to match expected pick list, but got diff: %s", cmp.Diff(tt.expected, actual))
+			}
+		})
+	}
+}
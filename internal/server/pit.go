@@ -0,0 +1,249 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/gorilla/mux"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+// Pit schema field types.
+const (
+	pitFieldNumber  = "number"
+	pitFieldBoolean = "boolean"
+	pitFieldText    = "text"
+	pitFieldList    = "list"
+)
+
+type pitFieldError struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// pitValidationError is returned when a pit report doesn't match the event's pit schema.
+type pitValidationError struct {
+	Fields []pitFieldError `json:"fields"`
+}
+
+// validatePitSchema checks that every field of a pit schema has a unique name and one of the
+// pit field types, and doesn't use any of the match schema summary properties.
+func validatePitSchema(fields store.SchemaFields) error {
+	seen := make(map[string]bool)
+
+	for _, field := range fields {
+		if field.Name == "" {
+			return errors.New("pit schema field has no name")
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("duplicate pit schema field %q", field.Name)
+		}
+		seen[field.Name] = true
+
+		switch field.Type {
+		case pitFieldNumber, pitFieldBoolean, pitFieldText, pitFieldList:
+		default:
+			return fmt.Errorf("pit schema field %q has invalid type %q", field.Name, field.Type)
+		}
+
+		if field.ReportReference != "" || field.TBAReference != "" || len(field.Sum) != 0 || len(field.AnyOf) != 0 || field.Expression != "" {
+			return fmt.Errorf("pit schema field %q can't be summarized", field.Name)
+		}
+	}
+
+	return nil
+}
+
+// validatePitData checks that every answer in a pit report is for a field of the pit schema,
+// and that its value matches the field's type. A nil schema (for events without one)
+// accepts any answers.
+func validatePitData(schema store.SchemaFields, data store.PitData) []pitFieldError {
+	errs := make([]pitFieldError, 0)
+	if schema == nil {
+		return errs
+	}
+
+	types := make(map[string]string)
+	for _, field := range schema {
+		types[field.Name] = field.Type
+	}
+
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fieldType, ok := types[name]
+		if !ok {
+			errs = append(errs, pitFieldError{Name: name, Reason: "not a field in the event's pit schema"})
+			continue
+		}
+
+		if !pitValueHasType(data[name], fieldType) {
+			errs = append(errs, pitFieldError{Name: name, Reason: fmt.Sprintf("expected %s value", fieldType)})
+		}
+	}
+
+	return errs
+}
+
+// pitValueHasType returns whether a pit report value decoded from JSON has a pit field type.
+func pitValueHasType(value interface{}, fieldType string) bool {
+	switch v := value.(type) {
+	case float64:
+		return fieldType == pitFieldNumber
+	case bool:
+		return fieldType == pitFieldBoolean
+	case string:
+		return fieldType == pitFieldText
+	case []interface{}:
+		if fieldType != pitFieldList {
+			return false
+		}
+		for _, item := range v {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// getPitReportsHandler returns a handler to get the pit reports for a team at an event from
+// the user's realm and realms that share reports.
+func (s *Server) getPitReportsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey, teamKey := vars["eventKey"], vars["teamKey"]
+
+		var realmID *int64
+		userRealmID, err := ihttp.GetRealmID(r)
+		if err == nil {
+			realmID = &userRealmID
+		}
+
+		reports, err := s.Store.GetEventTeamPitReportsForRealm(r.Context(), eventKey, teamKey, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("getting pit reports")
+			return
+		}
+
+		ihttp.Respond(w, reports, http.StatusOK)
+	}
+}
+
+// putPitReportHandler returns a handler to create or replace the user's realm's pit report
+// for a team at an event. The answers must match the event's pit schema, if it has one.
+func (s *Server) putPitReportHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey, teamKey := vars["eventKey"], vars["teamKey"]
+
+		var report store.PitReport
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err := validator.New().Struct(report); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		report.EventKey = eventKey
+		report.TeamKey = teamKey
+
+		reporterID, err := ihttp.GetSubject(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+		report.ReporterID = &reporterID
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+		report.RealmID = realmID
+
+		event, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		var schema store.SchemaFields
+		if event.PitSchemaID != nil {
+			pitSchema, err := s.Store.GetSchemaByID(r.Context(), *event.PitSchemaID)
+			if err != nil {
+				ihttp.Error(w, http.StatusInternalServerError)
+				s.Logger.WithError(err).Error("retrieving event pit schema")
+				return
+			}
+			schema = pitSchema.Schema
+		}
+
+		if fieldErrs := validatePitData(schema, report.Data); len(fieldErrs) != 0 {
+			ihttp.Respond(w, pitValidationError{Fields: fieldErrs}, http.StatusUnprocessableEntity)
+			return
+		}
+
+		created, err := s.Store.UpsertPitReport(r.Context(), report)
+		if errors.Is(err, store.ErrFKeyViolation{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("upserting pit report")
+			return
+		}
+
+		if created {
+			w.WriteHeader(http.StatusCreated)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+// deletePitReportHandler returns a handler to delete the user's realm's pit report for a
+// team at an event.
+func (s *Server) deletePitReportHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey, teamKey := vars["eventKey"], vars["teamKey"]
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		err = s.Store.DeletePitReport(r.Context(), eventKey, teamKey, realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("deleting pit report")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidatePitSchema(t *testing.T) {
+	testCases := []struct {
+		name      string
+		fields    store.SchemaFields
+		expectErr bool
+	}{
+		{
+			name: "valid",
+			fields: store.SchemaFields{
+				{FieldDescriptor: store.FieldDescriptor{Name: "Drivetrain"}, Type: "text"},
+				{FieldDescriptor: store.FieldDescriptor{Name: "Weight"}, Type: "number"},
+				{FieldDescriptor: store.FieldDescriptor{Name: "Buddy Climb"}, Type: "boolean"},
+				{FieldDescriptor: store.FieldDescriptor{Name: "Auto Routines"}, Type: "list"},
+			},
+		},
+		{
+			name:      "no name",
+			fields:    store.SchemaFields{{Type: "text"}},
+			expectErr: true,
+		},
+		{
+			name: "duplicate name",
+			fields: store.SchemaFields{
+				{FieldDescriptor: store.FieldDescriptor{Name: "Weight"}, Type: "number"},
+				{FieldDescriptor: store.FieldDescriptor{Name: "Weight"}, Type: "text"},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "invalid type",
+			fields:    store.SchemaFields{{FieldDescriptor: store.FieldDescriptor{Name: "Weight"}, Type: "float"}},
+			expectErr: true,
+		},
+		{
+			name:      "summarized",
+			fields:    store.SchemaFields{{FieldDescriptor: store.FieldDescriptor{Name: "Weight"}, Type: "number", ReportReference: "weight"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePitSchema(tt.fields)
+			if tt.expectErr != (err != nil) {
+				t.Errorf("expected error to be %t, but got: %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestValidatePitData(t *testing.T) {
+	schema := store.SchemaFields{
+		{FieldDescriptor: store.FieldDescriptor{Name: "Drivetrain"}, Type: "text"},
+		{FieldDescriptor: store.FieldDescriptor{Name: "Weight"}, Type: "number"},
+		{FieldDescriptor: store.FieldDescriptor{Name: "Buddy Climb"}, Type: "boolean"},
+		{FieldDescriptor: store.FieldDescriptor{Name: "Auto Routines"}, Type: "list"},
+	}
+
+	testCases := []struct {
+		name     string
+		schema   store.SchemaFields
+		data     store.PitData
+		expected []pitFieldError
+	}{
+		{
+			name:   "valid",
+			schema: schema,
+			data: store.PitData{
+				"Drivetrain":    "swerve",
+				"Weight":        118.5,
+				"Buddy Climb":   false,
+				"Auto Routines": []interface{}{"2 cargo", "hab only"},
+			},
+			expected: []pitFieldError{},
+		},
+		{
+			name:     "no schema",
+			data:     store.PitData{"anything": "goes"},
+			expected: []pitFieldError{},
+		},
+		{
+			name:   "invalid",
+			schema: schema,
+			data: store.PitData{
+				"Drivetrain":    4.0,
+				"Weight":        "heavy",
+				"Auto Routines": []interface{}{"2 cargo", 3.0},
+				"Wheels":        6.0,
+			},
+			expected: []pitFieldError{
+				{Name: "Auto Routines", Reason: "expected list value"},
+				{Name: "Drivetrain", Reason: "expected text value"},
+				{Name: "Weight", Reason: "expected number value"},
+				{Name: "Wheels", Reason: "not a field in the event's pit schema"},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validatePitData(tt.schema, tt.data)
+
+			if !cmp.Equal(tt.expected, errs) {
+				t.Errorf("expected errors to equal expected, but got diff: %s", cmp.Diff(tt.expected, errs))
+			}
+		})
+	}
+}
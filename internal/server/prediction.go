@@ -0,0 +1,206 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+	"github.com/gorilla/mux"
+)
+
+type teamContribution struct {
+	Team     string  `json:"team"`
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	Matches  int     `json:"matches"`
+}
+
+type alliancePrediction struct {
+	Teams    []teamContribution `json:"teams"`
+	Score    float64            `json:"score"`
+	Variance float64            `json:"variance"`
+}
+
+type matchPrediction struct {
+	Stat               string             `json:"stat"`
+	Red                alliancePrediction `json:"red"`
+	Blue               alliancePrediction `json:"blue"`
+	RedWinProbability  float64            `json:"redWinProbability"`
+	BlueWinProbability float64            `json:"blueWinProbability"`
+	ScoreDifference    float64            `json:"scoreDifference"`
+	DifferenceVariance float64            `json:"differenceVariance"`
+}
+
+// contribution summarizes each of a team's matches on their own to find the mean and
+// sample variance of a single stat. Matches without a value for the stat (e.g. unplayed
+// matches without reports) are skipped.
+func contribution(schema summary.Schema, team string, matches []summary.Match, stat string) (teamContribution, error) {
+	values := make([]float64, 0, len(matches))
+
+	for _, match := range matches {
+		matchSummary, err := summary.SummarizeTeam(schema, []summary.Match{match})
+		if err != nil {
+			return teamContribution{}, fmt.Errorf("unable to summarize match %s: %w", match.Key, err)
+		}
+
+		for _, summaryStat := range matchSummary {
+			if summaryStat.Name == stat {
+				values = append(values, summaryStat.Average)
+				break
+			}
+		}
+	}
+
+	c := teamContribution{Team: team, Matches: len(values)}
+	if len(values) == 0 {
+		return c, nil
+	}
+
+	for _, v := range values {
+		c.Mean += v
+	}
+	c.Mean /= float64(len(values))
+
+	if len(values) > 1 {
+		for _, v := range values {
+			c.Variance += (v - c.Mean) * (v - c.Mean)
+		}
+		c.Variance /= float64(len(values) - 1)
+	}
+
+	return c, nil
+}
+
+func newAlliancePrediction(teams []teamContribution) alliancePrediction {
+	prediction := alliancePrediction{Teams: teams}
+	for _, team := range teams {
+		prediction.Score += team.Mean
+		prediction.Variance += team.Variance
+	}
+
+	return prediction
+}
+
+// predictMatch sums each alliance's team contributions, treating them as independent,
+// and finds the probability of each alliance winning assuming the difference of the
+// alliance scores is normally distributed.
+func predictMatch(stat string, red, blue []teamContribution) matchPrediction {
+	prediction := matchPrediction{
+		Stat: stat,
+		Red:  newAlliancePrediction(red),
+		Blue: newAlliancePrediction(blue),
+	}
+
+	prediction.ScoreDifference = prediction.Red.Score - prediction.Blue.Score
+	prediction.DifferenceVariance = prediction.Red.Variance + prediction.Blue.Variance
+
+	switch {
+	case prediction.DifferenceVariance > 0:
+		z := prediction.ScoreDifference / math.Sqrt(2*prediction.DifferenceVariance)
+		prediction.RedWinProbability = 0.5 * (1 + math.Erf(z))
+	case prediction.ScoreDifference > 0:
+		prediction.RedWinProbability = 1
+	case prediction.ScoreDifference < 0:
+		prediction.RedWinProbability = 0
+	default:
+		prediction.RedWinProbability = 0.5
+	}
+	prediction.BlueWinProbability = 1 - prediction.RedWinProbability
+
+	return prediction
+}
+
+// matchPredictionHandler returns a handler to predict the outcome of a match from each
+// team's performance in the other matches at the event, using the stat the user's realm
+// has marked as the points stat for the event schema.
+func (s *Server) matchPredictionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey := vars["eventKey"]
+		matchKey := vars["matchKey"]
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		match, err := s.Store.GetMatchAnalysisInfoForRealm(r.Context(), eventKey, matchKey, &realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving match")
+			return
+		}
+
+		storeSchema, teamToMatches, err := s.eventTeamMatches(r.Context(), eventKey, &realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if errors.Is(err, errNoSchema) {
+			ihttp.Respond(w, errNoSchema, http.StatusBadRequest)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event team matches")
+			return
+		}
+
+		pointsStat, err := s.Store.GetPointsStat(r.Context(), storeSchema.ID, realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Respond(w, errors.New("no points stat set for event schema"), http.StatusBadRequest)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving points stat")
+			return
+		}
+
+		schema := storeSummaryToSummarySchema(storeSchema)
+
+		contributions := func(teams []string) ([]teamContribution, error) {
+			res := make([]teamContribution, 0, len(teams))
+			for _, team := range teams {
+				// leave out the match being predicted so already played matches
+				// aren't predicted with their own results
+				otherMatches := make([]summary.Match, 0, len(teamToMatches[team]))
+				for _, m := range teamToMatches[team] {
+					if m.Key != matchKey {
+						otherMatches = append(otherMatches, m)
+					}
+				}
+
+				c, err := contribution(schema, team, otherMatches, pointsStat.Name)
+				if err != nil {
+					return nil, fmt.Errorf("unable to find contribution for team %s: %w", team, err)
+				}
+
+				res = append(res, c)
+			}
+
+			return res, nil
+		}
+
+		red, err := contributions(match.RedAlliance)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("predicting red alliance")
+			return
+		}
+
+		blue, err := contributions(match.BlueAlliance)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("predicting blue alliance")
+			return
+		}
+
+		ihttp.Respond(w, predictMatch(pointsStat.Name, red, blue), http.StatusOK)
+	}
+}
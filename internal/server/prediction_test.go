@@ -0,0 +1,122 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestContribution(t *testing.T) {
+	schema := summary.Schema{
+		{FieldDescriptor: summary.FieldDescriptor{Name: "Points"}, ReportReference: "points"},
+	}
+
+	report := func(points float64) summary.Report {
+		return summary.Report{{Name: "points", Value: points}}
+	}
+
+	testCases := []struct {
+		name     string
+		matches  []summary.Match
+		expected teamContribution
+	}{
+		{
+			name:     "no matches",
+			expected: teamContribution{Team: "frc1"},
+		},
+		{
+			name: "unplayed match is skipped",
+			matches: []summary.Match{
+				{Key: "qm1", Reports: []summary.Report{report(10)}},
+				{Key: "qm2"},
+			},
+			expected: teamContribution{Team: "frc1", Mean: 10, Matches: 1},
+		},
+		{
+			name: "multiple reports for a match are averaged first",
+			matches: []summary.Match{
+				{Key: "qm1", Reports: []summary.Report{report(10), report(20)}},
+				{Key: "qm2", Reports: []summary.Report{report(5)}},
+				{Key: "qm3", Reports: []summary.Report{report(25)}},
+			},
+			expected: teamContribution{Team: "frc1", Mean: 15, Variance: 100, Matches: 3},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := contribution(schema, "frc1", tt.matches, "Points")
+			if err != nil {
+				t.Errorf("did not expect error but got: %v", err)
+				return
+			}
+
+			if !cmp.Equal(c, tt.expected) {
+				t.Errorf("expected contribution to match, but got diff: %s", cmp.Diff(tt.expected, c))
+			}
+		})
+	}
+}
+
+func TestPredictMatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		red      []teamContribution
+		blue     []teamContribution
+		expected matchPrediction
+	}{
+		{
+			name: "no variance",
+			red:  []teamContribution{{Team: "frc1", Mean: 10}, {Team: "frc2", Mean: 5}},
+			blue: []teamContribution{{Team: "frc3", Mean: 12}},
+			expected: matchPrediction{
+				Stat:               "Points",
+				Red:                alliancePrediction{Teams: []teamContribution{{Team: "frc1", Mean: 10}, {Team: "frc2", Mean: 5}}, Score: 15},
+				Blue:               alliancePrediction{Teams: []teamContribution{{Team: "frc3", Mean: 12}}, Score: 12},
+				RedWinProbability:  1,
+				BlueWinProbability: 0,
+				ScoreDifference:    3,
+			},
+		},
+		{
+			name: "even match",
+			red:  []teamContribution{{Team: "frc1", Mean: 10, Variance: 4}},
+			blue: []teamContribution{{Team: "frc2", Mean: 10, Variance: 5}},
+			expected: matchPrediction{
+				Stat:               "Points",
+				Red:                alliancePrediction{Teams: []teamContribution{{Team: "frc1", Mean: 10, Variance: 4}}, Score: 10, Variance: 4},
+				Blue:               alliancePrediction{Teams: []teamContribution{{Team: "frc2", Mean: 10, Variance: 5}}, Score: 10, Variance: 5},
+				RedWinProbability:  0.5,
+				BlueWinProbability: 0.5,
+				DifferenceVariance: 9,
+			},
+		},
+		{
+			name: "one standard deviation",
+			red:  []teamContribution{{Team: "frc1", Mean: 10, Variance: 4}},
+			blue: []teamContribution{{Team: "frc2", Mean: 13, Variance: 5}},
+			expected: matchPrediction{
+				Stat:               "Points",
+				Red:                alliancePrediction{Teams: []teamContribution{{Team: "frc1", Mean: 10, Variance: 4}}, Score: 10, Variance: 4},
+				Blue:               alliancePrediction{Teams: []teamContribution{{Team: "frc2", Mean: 13, Variance: 5}}, Score: 13, Variance: 5},
+				RedWinProbability:  0.1587,
+				BlueWinProbability: 0.8413,
+				ScoreDifference:    -3,
+				DifferenceVariance: 9,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			prediction := predictMatch("Points", tt.red, tt.blue)
+
+			approx := cmpopts.EquateApprox(0, 1e-4)
+			if !cmp.Equal(prediction, tt.expected, approx) {
+				t.Errorf("expected prediction to match, but got diff: %s", cmp.Diff(tt.expected, prediction, approx))
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/npmanos/4176Gameday-backend/internal/notify"
 	"github.com/npmanos/4176Gameday-backend/internal/store"
 
 	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
@@ -67,6 +70,35 @@ func (s *Server) putReport() http.HandlerFunc {
 		}
 		report.RealmID = &realmID
 
+		event, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		schema, err := s.eventReportSchema(r.Context(), event)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event schema")
+			return
+		}
+
+		// In lenient mode reports that don't match the schema are stored anyway, and the
+		// offending fields are returned as warnings.
+		lenient, _ := strconv.ParseBool(r.URL.Query().Get("lenient"))
+
+		fieldErrs := store.ValidateReportData(schema.Schema, report.Data)
+		if len(fieldErrs) != 0 && !lenient {
+			ihttp.Respond(w, reportValidationError{Fields: fieldErrs}, http.StatusUnprocessableEntity)
+			return
+		}
+
+		report.SetSchema(schema)
+
 		created, err := s.Store.UpsertReport(r.Context(), report)
 		if err != nil {
 			ihttp.Error(w, http.StatusInternalServerError)
@@ -74,7 +106,17 @@ func (s *Server) putReport() http.HandlerFunc {
 			return
 		}
 
-		if created {
+		s.publishReport(r.Context(), report)
+
+		if len(fieldErrs) != 0 {
+			s.Logger.WithField("eventKey", eventKey).WithField("fields", fieldErrs).Warn("stored report that does not match schema")
+
+			status := http.StatusOK
+			if created {
+				status = http.StatusCreated
+			}
+			ihttp.Respond(w, reportValidationError{Fields: fieldErrs}, status)
+		} else if created {
 			w.WriteHeader(http.StatusCreated)
 		} else {
 			w.WriteHeader(http.StatusNoContent)
@@ -82,6 +124,28 @@ func (s *Server) putReport() http.HandlerFunc {
 	}
 }
 
+// publishReport notifies event stream subscribers that a report was stored. If the
+// report's realm can't be retrieved, the report is treated as not shared.
+func (s *Server) publishReport(ctx context.Context, report store.Report) {
+	msg := notify.Message{
+		Type:     notify.TypeReport,
+		EventKey: report.EventKey,
+		MatchKey: report.MatchKey,
+		TeamKey:  report.TeamKey,
+		RealmID:  report.RealmID,
+	}
+
+	if report.RealmID != nil {
+		realm, err := s.Store.GetRealm(ctx, *report.RealmID)
+		if err != nil {
+			s.Logger.WithError(err).Error("retrieving report realm")
+		}
+		msg.Shared = realm.ShareReports
+	}
+
+	s.Broker.Publish(msg)
+}
+
 func (s *Server) leaderboardHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		realmID, err := ihttp.GetRealmID(r)
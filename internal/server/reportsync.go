@@ -0,0 +1,189 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/gorilla/mux"
+)
+
+type syncReport struct {
+	ClientID   string           `json:"clientId"`
+	MatchKey   string           `json:"matchKey"`
+	TeamKey    string           `json:"teamKey"`
+	ReporterID *int64           `json:"reporterId,omitempty"`
+	UpdatedAt  time.Time        `json:"updatedAt"`
+	Data       store.ReportData `json:"data"`
+	Comment    string           `json:"comment"`
+}
+
+type syncReportsRequest struct {
+	Since   int64        `json:"since"`
+	Reports []syncReport `json:"reports"`
+}
+
+type syncReportsResponse struct {
+	Results []store.ReportSyncResult `json:"results"`
+	Reports []syncReport             `json:"reports"`
+	Cursor  int64                    `json:"cursor"`
+}
+
+// validateSyncReport returns why a synced report can't be stored, or an empty string
+// if it can be. seen holds the client IDs of the reports before it in the batch.
+func validateSyncReport(report syncReport, seen map[string]bool) string {
+	switch {
+	case report.ClientID == "":
+		return "missing client ID"
+	case seen[report.ClientID]:
+		return "duplicate client ID"
+	case report.MatchKey == "" || report.TeamKey == "":
+		return "missing match or team key"
+	case report.UpdatedAt.IsZero():
+		return "missing updated at time"
+	}
+
+	return ""
+}
+
+// syncReportsHandler returns a handler for clients that store reports while offline to
+// upload them in one batch, and to retrieve the reports other scouts have made since the
+// cursor from their last sync.
+func (s *Server) syncReportsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		var req syncReportsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		reporterID, err := ihttp.GetSubject(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		event, err := s.Store.GetEventForRealm(r.Context(), eventKey, &realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		schema, err := s.eventReportSchema(r.Context(), event)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event schema")
+			return
+		}
+
+		// In lenient mode reports that don't match the schema are stored anyway, with the
+		// offending fields as the result's reason.
+		lenient, _ := strconv.ParseBool(r.URL.Query().Get("lenient"))
+
+		results := make([]store.ReportSyncResult, len(req.Reports))
+		reports := make([]store.Report, 0, len(req.Reports))
+		indices := make([]int, 0, len(req.Reports))
+		warnings := make(map[int]string)
+		seen := make(map[string]bool)
+
+		for i, report := range req.Reports {
+			if reason := validateSyncReport(report, seen); reason != "" {
+				results[i] = store.ReportSyncResult{ClientID: report.ClientID, Status: store.ReportRejected, Reason: reason}
+				continue
+			}
+			seen[report.ClientID] = true
+
+			if fieldErrs := store.ValidateReportData(schema.Schema, report.Data); len(fieldErrs) != 0 {
+				reason := store.ReportFieldErrorsReason(fieldErrs)
+				if !lenient {
+					results[i] = store.ReportSyncResult{ClientID: report.ClientID, Status: store.ReportRejected, Reason: reason}
+					continue
+				}
+				warnings[i] = reason
+			}
+
+			clientID := report.ClientID
+			storeReport := store.Report{
+				EventKey:   eventKey,
+				MatchKey:   report.MatchKey,
+				TeamKey:    report.TeamKey,
+				ReporterID: &reporterID,
+				RealmID:    &realmID,
+				Data:       report.Data,
+				Comment:    report.Comment,
+				ClientID:   &clientID,
+				UpdatedAt:  report.UpdatedAt,
+			}
+			storeReport.SetSchema(schema)
+			reports = append(reports, storeReport)
+			indices = append(indices, i)
+		}
+
+		synced, err := s.Store.SyncReports(r.Context(), reports)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("syncing reports")
+			return
+		}
+
+		for i, result := range synced {
+			if result.Status == store.ReportCreated || result.Status == store.ReportUpdated {
+				s.publishReport(r.Context(), reports[i])
+
+				if warning, ok := warnings[indices[i]]; ok {
+					result.Reason = warning
+				}
+			}
+
+			results[indices[i]] = result
+		}
+
+		others, cursor, err := s.Store.GetEventReportsSinceForRealm(r.Context(), eventKey, req.Since, reporterID, &realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving reports since cursor")
+			return
+		}
+
+		res := syncReportsResponse{
+			Results: results,
+			Reports: make([]syncReport, 0, len(others)),
+			Cursor:  cursor,
+		}
+
+		for _, report := range others {
+			var clientID string
+			if report.ClientID != nil {
+				clientID = *report.ClientID
+			}
+
+			res.Reports = append(res.Reports, syncReport{
+				ClientID:   clientID,
+				MatchKey:   report.MatchKey,
+				TeamKey:    report.TeamKey,
+				ReporterID: report.ReporterID,
+				UpdatedAt:  report.UpdatedAt,
+				Data:       report.Data,
+				Comment:    report.Comment,
+			})
+		}
+
+		ihttp.Respond(w, res, http.StatusOK)
+	}
+}
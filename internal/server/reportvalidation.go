@@ -0,0 +1,24 @@
+package server
+
+import (
+	"context"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+)
+
+// reportValidationError is returned when a report doesn't match the event's schema, or
+// alongside the stored report in lenient mode.
+type reportValidationError struct {
+	Fields []store.ReportFieldError `json:"fields"`
+}
+
+// eventReportSchema retrieves the schema that reports for an event are validated against
+// and stored under, or an empty schema (with nil fields and version 0) if the event has no
+// schema.
+func (s *Server) eventReportSchema(ctx context.Context, event store.Event) (store.Schema, error) {
+	if event.SchemaID == nil {
+		return store.Schema{}, nil
+	}
+
+	return s.Store.GetSchemaByID(ctx, *event.SchemaID)
+}
@@ -11,7 +11,7 @@ import (
 func (s *Server) registerRoutes() *mux.Router {
 	r := mux.NewRouter()
 
-	r.Handle("/", healthHandler(s.uptime, s.TBA, s.Store)).Methods(http.MethodGet)
+	r.Handle("/", healthHandler(s.uptime, s.TBA, s.Store, s.nextPolls)).Methods(http.MethodGet)
 	r.Handle("/openapi.yaml", openAPIHandler(openAPI)).Methods(http.MethodGet)
 
 	r.Handle("/authenticate", authenticateHandler(s.Logger, time.Now, s.Store, s.JWTSecret)).Methods(http.MethodPost)
@@ -25,7 +25,14 @@ func (s *Server) registerRoutes() *mux.Router {
 
 	r.Handle("/schemas", ihttp.ACL(s.getSchemasHandler(), false, false, false)).Methods(http.MethodGet)
 	r.Handle("/schemas", ihttp.ACL(s.createSchemaHandler(), true, true, true)).Methods(http.MethodPost)
+	r.Handle("/schemas/preview", ihttp.ACL(s.previewSchemaHandler(), false, false, true)).Methods(http.MethodPost)
 	r.Handle("/schemas/{id}", ihttp.ACL(s.getSchemaByIDHandler(), false, false, false)).Methods(http.MethodGet)
+	r.Handle("/schemas/{id}", ihttp.ACL(s.updateSchemaHandler(), true, true, true)).Methods(http.MethodPut)
+	r.Handle("/schemas/{id}", ihttp.ACL(s.deleteSchemaHandler(), true, true, true)).Methods(http.MethodDelete)
+	r.Handle("/schemas/{id}/versions", ihttp.ACL(s.addSchemaVersionHandler(), true, true, true)).Methods(http.MethodPost)
+	r.Handle("/schemas/{id}/migrate-reports", ihttp.ACL(s.migrateSchemaReportsHandler(), true, true, true)).Methods(http.MethodPost)
+	r.Handle("/schemas/{id}/points-stat", ihttp.ACL(s.getPointsStatHandler(), false, false, true)).Methods(http.MethodGet)
+	r.Handle("/schemas/{id}/points-stat", ihttp.ACL(s.setPointsStatHandler(), true, true, true)).Methods(http.MethodPut)
 
 	r.Handle("/years", s.eventYearsHandler()).Methods(http.MethodGet)
 
@@ -33,7 +40,29 @@ func (s *Server) registerRoutes() *mux.Router {
 	r.Handle("/events/{eventKey}", ihttp.ACL(s.upsertEventHandler(), true, true, true)).Methods(http.MethodPut)
 	r.Handle("/events/{eventKey}", s.eventHandler()).Methods(http.MethodGet)
 
+	r.Handle("/events/{eventKey}/schema", ihttp.ACL(s.setEventSchemaHandler(), true, true, true)).Methods(http.MethodPut)
+	r.Handle("/events/{eventKey}/schema", ihttp.ACL(s.deleteEventSchemaHandler(), true, true, true)).Methods(http.MethodDelete)
 	r.Handle("/events/{eventKey}/stats", s.eventStats()).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/export/reports", s.exportReportsHandler()).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/export/stats", s.exportStatsHandler()).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/export/matches", s.exportMatchesHandler()).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/stream", s.eventStreamHandler()).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/opr", s.eventOPRsHandler()).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/coverage", ihttp.ACL(s.eventCoverageHandler(), false, false, true)).Methods(http.MethodGet)
+
+	r.Handle("/events/{eventKey}/picklists", ihttp.ACL(s.getPickListsHandler(), false, false, true)).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/picklists", ihttp.ACL(s.createPickListHandler(), false, true, true)).Methods(http.MethodPost)
+	r.Handle("/events/{eventKey}/picklists/{id}", ihttp.ACL(s.getPickListHandler(), false, false, true)).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/picklists/{id}", ihttp.ACL(s.updatePickListHandler(), false, true, true)).Methods(http.MethodPut)
+	r.Handle("/events/{eventKey}/picklists/{id}", ihttp.ACL(s.deletePickListHandler(), false, true, true)).Methods(http.MethodDelete)
+
+	r.Handle("/events/{eventKey}/assignments", ihttp.ACL(s.getAssignmentsHandler(), false, false, true)).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/assignments", ihttp.ACL(s.scheduleAssignmentsHandler(), true, true, true)).Methods(http.MethodPut)
+	r.Handle("/events/{eventKey}/assignments", ihttp.ACL(s.deleteAssignmentsHandler(), true, true, true)).Methods(http.MethodDelete)
+
+	r.Handle("/events/{eventKey}/alliance-selection", ihttp.ACL(s.allianceSelectionHandler(), false, false, true)).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/alliance-selection", ihttp.ACL(s.allianceSelectionActionHandler(), false, true, true)).Methods(http.MethodPost)
+	r.Handle("/events/{eventKey}/alliance-selection", ihttp.ACL(s.resetAllianceSelectionHandler(), true, true, true)).Methods(http.MethodDelete)
 
 	r.Handle("/events/{eventKey}/matches", s.matchesHandler()).Methods(http.MethodGet)
 	r.Handle("/events/{eventKey}/matches/{matchKey}", s.matchHandler()).Methods(http.MethodGet)
@@ -42,13 +71,29 @@ func (s *Server) registerRoutes() *mux.Router {
 
 	r.Handle("/events/{eventKey}/teams", s.eventTeamsHandler()).Methods(http.MethodGet)
 	r.Handle("/events/{eventKey}/teams/{teamKey}", s.eventTeamHandler()).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/teams/{teamKey}/stats/timeseries", s.teamStatTimeSeriesHandler()).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/teams/{teamKey}/pit", ihttp.ACL(s.getPitReportsHandler(), false, false, false)).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/teams/{teamKey}/pit", ihttp.ACL(s.putPitReportHandler(), false, true, true)).Methods(http.MethodPut)
+	r.Handle("/events/{eventKey}/teams/{teamKey}/pit", ihttp.ACL(s.deletePitReportHandler(), false, true, true)).Methods(http.MethodDelete)
+	r.Handle("/events/{eventKey}/teams/{teamKey}/comments", ihttp.ACL(s.getCommentsHandler(), false, false, false)).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/teams/{teamKey}/comments", ihttp.ACL(s.createCommentHandler(), false, true, true)).Methods(http.MethodPost)
+	r.Handle("/events/{eventKey}/teams/{teamKey}/comments/{id}", ihttp.ACL(s.updateCommentHandler(), false, true, true)).Methods(http.MethodPut)
+	r.Handle("/events/{eventKey}/teams/{teamKey}/comments/{id}", ihttp.ACL(s.deleteCommentHandler(), false, true, true)).Methods(http.MethodDelete)
 
 	r.Handle("/events/{eventKey}/matches/{matchKey}/reports/{teamKey}", ihttp.ACL(s.getReports(), false, false, false)).Methods(http.MethodGet)
 	r.Handle("/events/{eventKey}/matches/{matchKey}/reports/{teamKey}", ihttp.ACL(s.putReport(), false, true, true)).Methods(http.MethodPut)
+	r.Handle("/events/{eventKey}/matches/{matchKey}/comments/{teamKey}", ihttp.ACL(s.getCommentsHandler(), false, false, false)).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/matches/{matchKey}/comments/{teamKey}", ihttp.ACL(s.createCommentHandler(), false, true, true)).Methods(http.MethodPost)
+	r.Handle("/events/{eventKey}/matches/{matchKey}/comments/{teamKey}/{id}", ihttp.ACL(s.updateCommentHandler(), false, true, true)).Methods(http.MethodPut)
+	r.Handle("/events/{eventKey}/matches/{matchKey}/comments/{teamKey}/{id}", ihttp.ACL(s.deleteCommentHandler(), false, true, true)).Methods(http.MethodDelete)
+	r.Handle("/events/{eventKey}/reports/sync", ihttp.ACL(s.syncReportsHandler(), false, true, true)).Methods(http.MethodPost)
+	r.Handle("/reports/import", ihttp.ACL(s.importReportsHandler(), true, true, true)).Methods(http.MethodPost)
 
 	r.Handle("/events/{eventKey}/matches/{matchKey}/teams/{teamKey}/stats", s.matchTeamStats()).Methods(http.MethodGet)
+	r.Handle("/events/{eventKey}/matches/{matchKey}/prediction", ihttp.ACL(s.matchPredictionHandler(), false, false, true)).Methods(http.MethodGet)
 
 	r.Handle("/leaderboard", s.leaderboardHandler()).Methods(http.MethodGet)
+	r.Handle("/search", ihttp.ACL(s.searchHandler(), false, false, false)).Methods(http.MethodGet)
 
 	r.Handle("/realms", s.realmsHandler()).Methods(http.MethodGet)
 	r.Handle("/realms", s.createRealmHandler()).Methods(http.MethodPost)
@@ -57,6 +102,11 @@ func (s *Server) registerRoutes() *mux.Router {
 	r.Handle("/realms/{id}", ihttp.ACL(s.deleteRealmHandler(), true, true, true)).Methods(http.MethodDelete)
 
 	r.Handle("/teams/{teamKey}", s.teamHandler()).Methods(http.MethodGet)
+	r.Handle("/teams/{teamKey}/seasons/{year}", s.teamSeasonHandler()).Methods(http.MethodGet)
+
+	if s.TBAWebhookSecret != "" {
+		r.Handle("/tba/webhook", s.tbaWebhookHandler()).Methods(http.MethodPost)
+	}
 
 	return r
 }
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type schemaPreviewRequest struct {
+	EventKey string       `json:"eventKey" validate:"required"`
+	Schema   store.Schema `json:"schema"`
+}
+
+type undefinedReference struct {
+	Field     string `json:"field"`
+	Reference string `json:"reference"`
+}
+
+type invalidField struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+type schemaDiagnostics struct {
+	UnresolvedReportReferences []string             `json:"unresolvedReportReferences"`
+	UnmatchedTBAReferences     []string             `json:"unmatchedTbaReferences"`
+	UndefinedReferences        []undefinedReference `json:"undefinedReferences"`
+	InvalidFields              []invalidField       `json:"invalidFields"`
+}
+
+type schemaPreview struct {
+	Teams       []teamAnalysis    `json:"teams"`
+	Diagnostics schemaDiagnostics `json:"diagnostics"`
+}
+
+func schemaDiagnosticsFromSummary(diagnostics summary.Diagnostics) schemaDiagnostics {
+	sd := schemaDiagnostics{
+		UnresolvedReportReferences: diagnostics.UnresolvedReportReferences,
+		UnmatchedTBAReferences:     diagnostics.UnmatchedTBAReferences,
+		UndefinedReferences:        make([]undefinedReference, 0, len(diagnostics.UndefinedReferences)),
+		InvalidFields:              make([]invalidField, 0, len(diagnostics.InvalidFields)),
+	}
+
+	for _, ref := range diagnostics.UndefinedReferences {
+		sd.UndefinedReferences = append(sd.UndefinedReferences, undefinedReference{Field: ref.Field, Reference: ref.Reference})
+	}
+
+	for _, field := range diagnostics.InvalidFields {
+		sd.InvalidFields = append(sd.InvalidFields, invalidField{Field: field.Field, Error: field.Err.Error()})
+	}
+
+	return sd
+}
+
+// previewSchemaHandler returns a handler to summarize every team at an event with an unsaved
+// schema, the same as the event stats, along with diagnostics for fields that never have a
+// value or can't be summarized. If any fields can't be summarized no teams are returned.
+func (s *Server) previewSchemaHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req schemaPreviewRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if req.Schema.Kind == store.SchemaKindPit {
+			ihttp.Respond(w, errors.New("pit schemas can't be summarized"), http.StatusUnprocessableEntity)
+			return
+		}
+
+		var realmID *int64
+		userRealmID, err := ihttp.GetRealmID(r)
+		if err == nil {
+			realmID = &userRealmID
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), req.EventKey, realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		teamMatches, err := s.teamMatchesForEvent(r.Context(), req.EventKey, realmID, req.Schema)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event team matches")
+			return
+		}
+
+		schema := storeSummaryToSummarySchema(req.Schema)
+
+		var matches []summary.Match
+		for _, teamMatch := range teamMatches {
+			matches = append(matches, teamMatch...)
+		}
+
+		diagnostics := summary.Diagnose(schema, matches)
+		preview := schemaPreview{
+			Teams:       make([]teamAnalysis, 0),
+			Diagnostics: schemaDiagnosticsFromSummary(diagnostics),
+		}
+
+		if len(diagnostics.InvalidFields) == 0 {
+			for team, matches := range teamMatches {
+				teamSummary, err := summary.SummarizeTeam(schema, matches)
+				if err != nil {
+					ihttp.Error(w, http.StatusInternalServerError)
+					s.Logger.WithError(err).WithField("team", team).Error("summarizing team")
+					return
+				}
+
+				preview.Teams = append(preview.Teams, teamAnalysisFromSummary(teamSummary, team))
+			}
+		}
+
+		ihttp.Respond(w, preview, http.StatusOK)
+	}
+}
@@ -1,13 +1,17 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
 	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
 	"github.com/gorilla/mux"
 )
 
@@ -19,6 +23,15 @@ func (s *Server) createSchemaHandler() http.HandlerFunc {
 			return
 		}
 
+		if schema.Kind == "" {
+			schema.Kind = store.SchemaKindMatch
+		}
+
+		if err := validateSchema(schema); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
 		roles := ihttp.GetRoles(r)
 		if schema.Year != nil && !roles.IsSuperAdmin {
 			ihttp.Error(w, http.StatusForbidden)
@@ -52,6 +65,176 @@ func (s *Server) createSchemaHandler() http.HandlerFunc {
 	}
 }
 
+type schemaInUse struct {
+	Events []string `json:"events"`
+}
+
+// errSchemaFieldsChanged is returned when a schema update changes the schema's fields, which
+// would leave reports of the current version out of stats.
+var errSchemaFieldsChanged = errors.New("schema fields can only be changed by adding a version with POST /schemas/{id}/versions")
+
+// sameSchemaFields returns whether two sets of schema fields are the same once encoded.
+func sameSchemaFields(a, b store.SchemaFields) bool {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(aJSON, bJSON)
+}
+
+// updateSchemaHandler returns a handler to update a schema's year and realm in place. The
+// schema's kind can't be changed, and its fields can only be changed by adding a version.
+// If any events reference the schema, the update is refused unless forced.
+func (s *Server) updateSchemaHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		var schema store.Schema
+		if err := json.NewDecoder(r.Body).Decode(&schema); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+		schema.ID = id
+
+		existing, err := s.Store.GetSchemaByID(r.Context(), id)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("getting schema by id")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+		schema.Kind = existing.Kind
+
+		if schema.Schema == nil {
+			schema.Schema = existing.Schema
+		} else if !sameSchemaFields(schema.Schema, existing.Schema) {
+			ihttp.Respond(w, errSchemaFieldsChanged, http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err := validateSchema(schema); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		roles := ihttp.GetRoles(r)
+		if !canEditSchema(r, existing) || (schema.Year != nil && !roles.IsSuperAdmin) {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		var editorRealmID *int64
+		if realmID, err := ihttp.GetRealmID(r); err == nil {
+			editorRealmID = &realmID
+		}
+
+		realmID, ok := updatedSchemaRealmID(existing, schema, editorRealmID)
+		if !ok {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+		schema.RealmID = realmID
+
+		if !s.checkSchemaUnused(w, r, id) {
+			return
+		}
+
+		err = s.Store.UpdateSchema(r.Context(), schema)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if errors.Is(err, store.ErrExists{}) {
+			ihttp.Respond(w, err, http.StatusConflict)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("updating schema")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// deleteSchemaHandler returns a handler to delete a schema. If any events reference the
+// schema, the delete is refused unless forced, in which case the events fall back to the
+// schema for their year.
+func (s *Server) deleteSchemaHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		schema, err := s.Store.GetSchemaByID(r.Context(), id)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("getting schema by id")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		if !canEditSchema(r, schema) {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		if !s.checkSchemaUnused(w, r, id) {
+			return
+		}
+
+		err = s.Store.DeleteSchema(r.Context(), id)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("deleting schema")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// checkSchemaUnused responds with a conflict listing the events that reference a schema,
+// unless there are none or the force query parameter is true. It returns whether the
+// request should continue.
+func (s *Server) checkSchemaUnused(w http.ResponseWriter, r *http.Request, id int64) bool {
+	if force, _ := strconv.ParseBool(r.URL.Query().Get("force")); force {
+		return true
+	}
+
+	eventKeys, err := s.Store.GetSchemaEventKeys(r.Context(), id)
+	if err != nil {
+		s.Logger.WithError(err).Error("getting schema events")
+		ihttp.Error(w, http.StatusInternalServerError)
+		return false
+	}
+
+	if len(eventKeys) != 0 {
+		ihttp.Respond(w, schemaInUse{Events: eventKeys}, http.StatusConflict)
+		return false
+	}
+
+	return true
+}
+
 func (s *Server) getSchemasHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if yearQuery := r.URL.Query().Get("year"); yearQuery != "" {
@@ -126,3 +309,160 @@ func (s *Server) getSchemaByIDHandler() http.HandlerFunc {
 		ihttp.Respond(w, schema, http.StatusOK)
 	}
 }
+
+// validateSchema checks a schema's kind and fields. Match schemas must be possible to
+// summarize, and pit schemas must follow validatePitSchema.
+func validateSchema(schema store.Schema) error {
+	switch schema.Kind {
+	case store.SchemaKindMatch:
+		return summary.ValidateSchema(storeSummaryToSummarySchema(schema))
+	case store.SchemaKindPit:
+		return validatePitSchema(schema.Schema)
+	default:
+		return fmt.Errorf("unknown schema kind %q", schema.Kind)
+	}
+}
+
+// updatedSchemaRealmID returns the realm a schema belongs to after an update. A realm
+// schema stays in its realm unless it's made a year schema, and a year schema made a realm
+// schema moves to the editor's realm, so it can't be converted by an editor without one.
+func updatedSchemaRealmID(existing, updated store.Schema, editorRealmID *int64) (*int64, bool) {
+	switch {
+	case updated.Year != nil:
+		return nil, true
+	case existing.RealmID != nil:
+		return existing.RealmID, true
+	case editorRealmID != nil:
+		return editorRealmID, true
+	default:
+		return nil, false
+	}
+}
+
+// canEditSchema returns whether the user may change a schema. Only super admins may change
+// standard FRC schemas, and realm admins may change their realm's schemas.
+func canEditSchema(r *http.Request, schema store.Schema) bool {
+	if ihttp.GetRoles(r).IsSuperAdmin {
+		return true
+	}
+
+	if schema.Year != nil || schema.RealmID == nil {
+		return false
+	}
+
+	realmID, err := ihttp.GetRealmID(r)
+	return err == nil && realmID == *schema.RealmID
+}
+
+// schemaVisibleToRealm returns whether a realm may use a schema. Standard FRC schemas, the
+// realm's own schemas, and schemas from realms that share reports are visible.
+func (s *Server) schemaVisibleToRealm(ctx context.Context, schema store.Schema, realmID int64) (bool, error) {
+	if schema.Year != nil || schema.RealmID == nil || *schema.RealmID == realmID {
+		return true, nil
+	}
+
+	realm, err := s.Store.GetRealm(ctx, *schema.RealmID)
+	if errors.Is(err, store.ErrNoResults{}) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return realm.ShareReports, nil
+}
+
+func (s *Server) getPointsStatHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		pointsStat, err := s.Store.GetPointsStat(r.Context(), id, realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("getting points stat")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		ihttp.Respond(w, pointsStat, http.StatusOK)
+	}
+}
+
+// setPointsStatHandler returns a handler to mark which field of a schema the user's
+// realm uses as a team's points contribution for match predictions.
+func (s *Server) setPointsStatHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		var pointsStat store.PointsStat
+		if err := json.NewDecoder(r.Body).Decode(&pointsStat); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		realmID, err := ihttp.GetRealmID(r)
+		if err != nil {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		schema, err := s.Store.GetSchemaByID(r.Context(), id)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("getting schema by id")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		visible, err := s.schemaVisibleToRealm(r.Context(), schema, realmID)
+		if err != nil {
+			s.Logger.WithError(err).Error("checking schema visibility")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		} else if !visible {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		var found bool
+		for _, field := range schema.Schema {
+			if field.Name == pointsStat.Name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			ihttp.Respond(w, fmt.Errorf("schema %d has no field %q", id, pointsStat.Name), http.StatusUnprocessableEntity)
+			return
+		}
+
+		pointsStat.SchemaID = id
+		pointsStat.RealmID = realmID
+
+		if err := s.Store.SetPointsStat(r.Context(), pointsStat); err != nil {
+			s.Logger.WithError(err).Error("setting points stat")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpdatedSchemaRealmID(t *testing.T) {
+	year, realmID, editorRealmID := int64(2019), int64(1), int64(2)
+
+	testCases := []struct {
+		name          string
+		existing      store.Schema
+		updated       store.Schema
+		editorRealmID *int64
+		expected      *int64
+		expectedOK    bool
+	}{
+		{
+			name:          "realm schema stays in its realm",
+			existing:      store.Schema{RealmID: &realmID},
+			editorRealmID: &editorRealmID,
+			expected:      &realmID,
+			expectedOK:    true,
+		},
+		{
+			name:       "realm schema edited without a realm",
+			existing:   store.Schema{RealmID: &realmID},
+			expected:   &realmID,
+			expectedOK: true,
+		},
+		{
+			name:          "realm schema made a year schema",
+			existing:      store.Schema{RealmID: &realmID},
+			updated:       store.Schema{Year: &year},
+			editorRealmID: &editorRealmID,
+			expectedOK:    true,
+		},
+		{
+			name:          "year schema made a realm schema",
+			existing:      store.Schema{Year: &year},
+			editorRealmID: &editorRealmID,
+			expected:      &editorRealmID,
+			expectedOK:    true,
+		},
+		{
+			name:     "year schema made a realm schema without a realm",
+			existing: store.Schema{Year: &year},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := updatedSchemaRealmID(tt.existing, tt.updated, tt.editorRealmID)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok to be %t, but got %t", tt.expectedOK, ok)
+			}
+
+			if !cmp.Equal(tt.expected, actual) {
+				t.Errorf("expected realm ID to equal expected, but got diff: %s", cmp.Diff(tt.expected, actual))
+			}
+		})
+	}
+}
+
+func TestSameSchemaFields(t *testing.T) {
+	existing := store.SchemaFields{
+		{FieldDescriptor: store.FieldDescriptor{Name: "Cargo"}, ReportReference: "cargo"},
+		{FieldDescriptor: store.FieldDescriptor{Name: "Climb"}, TBAReference: "endgameRobot"},
+	}
+
+	testCases := []struct {
+		name     string
+		updated  store.SchemaFields
+		expected bool
+	}{
+		{
+			name: "same fields",
+			updated: store.SchemaFields{
+				{FieldDescriptor: store.FieldDescriptor{Name: "Cargo"}, ReportReference: "cargo"},
+				{FieldDescriptor: store.FieldDescriptor{Name: "Climb"}, TBAReference: "endgameRobot"},
+			},
+			expected: true,
+		},
+		{
+			name: "empty sum",
+			updated: store.SchemaFields{
+				{FieldDescriptor: store.FieldDescriptor{Name: "Cargo"}, ReportReference: "cargo", Sum: []store.FieldDescriptor{}},
+				{FieldDescriptor: store.FieldDescriptor{Name: "Climb"}, TBAReference: "endgameRobot"},
+			},
+			expected: true,
+		},
+		{
+			name: "renamed field",
+			updated: store.SchemaFields{
+				{FieldDescriptor: store.FieldDescriptor{Name: "Balls"}, ReportReference: "cargo"},
+				{FieldDescriptor: store.FieldDescriptor{Name: "Climb"}, TBAReference: "endgameRobot"},
+			},
+		},
+		{
+			name: "removed field",
+			updated: store.SchemaFields{
+				{FieldDescriptor: store.FieldDescriptor{Name: "Cargo"}, ReportReference: "cargo"},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := sameSchemaFields(existing, tt.updated); actual != tt.expected {
+				t.Errorf("expected same fields to be %t, but got %t", tt.expected, actual)
+			}
+		})
+	}
+}
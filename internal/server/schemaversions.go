@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+	"github.com/gorilla/mux"
+)
+
+type schemaVersionRequest struct {
+	Schema store.SchemaFields `json:"schema"`
+	Rename map[string]string  `json:"rename"`
+	Scale  map[string]float64 `json:"scale"`
+}
+
+type migratedReports struct {
+	Version  int64 `json:"version"`
+	Migrated int64 `json:"migrated"`
+}
+
+// addSchemaVersionHandler returns a handler to replace a schema's fields with a new version.
+// Reports stored under older versions are read with the new version's renames and scales
+// applied, so they keep counting towards stats.
+func (s *Server) addSchemaVersionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		var req schemaVersionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			ihttp.Error(w, http.StatusUnprocessableEntity)
+			return
+		}
+
+		schema, err := s.Store.GetSchemaByID(r.Context(), id)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("getting schema by id")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		if !canEditSchema(r, schema) {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		if err := validateSchema(store.Schema{Kind: schema.Kind, Schema: req.Schema}); err != nil {
+			ihttp.Respond(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+
+		schema, err = s.Store.AddSchemaVersion(r.Context(), id, req.Schema, store.SchemaMigration{
+			Rename: req.Rename,
+			Scale:  req.Scale,
+		})
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("adding schema version")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		ihttp.Respond(w, schema, http.StatusOK)
+	}
+}
+
+// migrateSchemaReportsHandler returns a handler to rewrite the data of every report stored
+// against an older version of a schema to the schema's latest version.
+func (s *Server) migrateSchemaReportsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		schema, err := s.Store.GetSchemaByID(r.Context(), id)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			s.Logger.WithError(err).Error("getting schema by id")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		if !canEditSchema(r, schema) {
+			ihttp.Error(w, http.StatusForbidden)
+			return
+		}
+
+		migrations := storeMigrationsToSummaryMigrations(schema)
+		migrated, err := s.Store.MigrateSchemaReports(r.Context(), id, schema.Version, func(data store.ReportData, fromVersion int64) store.ReportData {
+			return migrateReportData(data, fromVersion, migrations)
+		})
+		if err != nil {
+			s.Logger.WithError(err).Error("migrating schema reports")
+			ihttp.Error(w, http.StatusInternalServerError)
+			return
+		}
+
+		ihttp.Respond(w, migratedReports{Version: schema.Version, Migrated: migrated}, http.StatusOK)
+	}
+}
+
+// migrateReportData updates report data stored under a version of a schema to the latest
+// version.
+func migrateReportData(data store.ReportData, version int64, migrations []summary.Migration) store.ReportData {
+	report := make(summary.Report, 0, len(data))
+	for _, stat := range data {
+		report = append(report, summary.ReportField{Name: stat.Name, Value: stat.Value})
+	}
+
+	migrated := make(store.ReportData, 0, len(data))
+	for _, field := range summary.MigrateReport(report, version, migrations) {
+		migrated = append(migrated, store.Stat{Name: field.Name, Value: field.Value})
+	}
+
+	return migrated
+}
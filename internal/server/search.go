@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+)
+
+// searchLimit is the most team hits and events returned by a search.
+const searchLimit = 100
+
+// searchHandler returns a handler to search report comments, comments, team nicknames, and
+// event names for the words of the q query parameter. Matching teams are returned with
+// their matching text, along with matching events.
+func (s *Server) searchHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+		if query == "" {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		var realmID *int64
+		userRealmID, err := ihttp.GetRealmID(r)
+		if err == nil {
+			realmID = &userRealmID
+		}
+
+		results, err := s.Store.Search(r.Context(), query, realmID, searchLimit)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("searching")
+			return
+		}
+
+		ihttp.Respond(w, results, http.StatusOK)
+	}
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+	"github.com/gorilla/mux"
+)
+
+type seasonEvent struct {
+	EventKey     string        `json:"eventKey"`
+	Name         string        `json:"name"`
+	StartDate    time.Time     `json:"startDate"`
+	SchemaID     *int64        `json:"schemaId,omitempty"`
+	Rank         *int          `json:"rank,omitempty"`
+	RankingScore *float64      `json:"rankingScore,omitempty"`
+	Summary      []summaryStat `json:"summary"`
+}
+
+type teamSeason struct {
+	Team     string        `json:"team"`
+	Year     int           `json:"year"`
+	SchemaID *int64        `json:"schemaId,omitempty"`
+	Events   []seasonEvent `json:"events"`
+	Summary  []summaryStat `json:"summary"`
+}
+
+// seasonSchemaID returns the schema used by the most events, preferring the schema of the
+// later event on a tie, or nil if none of the events have a schema.
+func seasonSchemaID(events []store.Event) *int64 {
+	counts := make(map[int64]int)
+
+	var schemaID *int64
+	for _, event := range events {
+		if event.SchemaID == nil {
+			continue
+		}
+
+		counts[*event.SchemaID]++
+		if schemaID == nil || counts[*event.SchemaID] >= counts[*schemaID] {
+			schemaID = event.SchemaID
+		}
+	}
+
+	return schemaID
+}
+
+// teamSeasonHandler returns a handler to summarize a team at every event it attended in a
+// year, and across all of them combined. Only events that share a schema (the schema used
+// by the most events) can be summarized together, so other events have no summary.
+func (s *Server) teamSeasonHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		teamKey := vars["teamKey"]
+
+		year, err := strconv.Atoi(vars["year"])
+		if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		var realmID *int64
+		userRealmID, err := ihttp.GetRealmID(r)
+		if err == nil {
+			realmID = &userRealmID
+		}
+
+		if _, err := s.Store.GetTeam(r.Context(), teamKey); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving team info")
+			return
+		}
+
+		events, err := s.Store.GetTeamEventsForRealm(r.Context(), teamKey, year, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving team events")
+			return
+		}
+
+		season := teamSeason{
+			Team:     teamKey,
+			Year:     year,
+			SchemaID: seasonSchemaID(events),
+			Events:   make([]seasonEvent, 0, len(events)),
+			Summary:  make([]summaryStat, 0),
+		}
+
+		var schema summary.Schema
+		var seasonMatches []summary.Match
+
+		for _, event := range events {
+			se := seasonEvent{
+				EventKey:  event.Key,
+				Name:      event.Name,
+				StartDate: event.StartDate,
+				SchemaID:  event.SchemaID,
+				Summary:   make([]summaryStat, 0),
+			}
+
+			eventTeam, err := s.Store.GetEventTeamForRealm(r.Context(), teamKey, event.Key, realmID)
+			if err != nil {
+				ihttp.Error(w, http.StatusInternalServerError)
+				s.Logger.WithError(err).Error("retrieving team rankings data")
+				return
+			}
+			se.Rank, se.RankingScore = eventTeam.Rank, eventTeam.RankingScore
+
+			if event.SchemaID != nil && *event.SchemaID == *season.SchemaID {
+				storeSchema, teamMatches, err := s.eventTeamMatches(r.Context(), event.Key, realmID)
+				if err != nil {
+					ihttp.Error(w, http.StatusInternalServerError)
+					s.Logger.WithError(err).Error("retrieving event team matches")
+					return
+				}
+				schema = storeSummaryToSummarySchema(storeSchema)
+
+				eventSummary, err := summary.SummarizeTeam(schema, teamMatches[teamKey])
+				if err != nil {
+					ihttp.Error(w, http.StatusInternalServerError)
+					s.Logger.WithError(err).WithField("team", teamKey).Error("summarizing team")
+					return
+				}
+				se.Summary = teamAnalysisFromSummary(eventSummary, teamKey).Summary
+
+				seasonMatches = append(seasonMatches, teamMatches[teamKey]...)
+			}
+
+			season.Events = append(season.Events, se)
+		}
+
+		if schema != nil {
+			seasonSummary, err := summary.SummarizeTeam(schema, seasonMatches)
+			if err != nil {
+				ihttp.Error(w, http.StatusInternalServerError)
+				s.Logger.WithError(err).WithField("team", teamKey).Error("summarizing team season")
+				return
+			}
+			season.Summary = teamAnalysisFromSummary(seasonSummary, teamKey).Summary
+		}
+
+		ihttp.Respond(w, season, http.StatusOK)
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSeasonSchemaID(t *testing.T) {
+	id := func(id int64) *int64 {
+		return &id
+	}
+
+	testCases := []struct {
+		name     string
+		events   []store.Event
+		expected *int64
+	}{
+		{
+			name: "no events",
+		},
+		{
+			name:   "no schemas",
+			events: []store.Event{{Key: "2019abca"}, {Key: "2019onto"}},
+		},
+		{
+			name:     "most common schema",
+			events:   []store.Event{{SchemaID: id(2)}, {SchemaID: id(1)}, {}, {SchemaID: id(2)}, {SchemaID: id(1)}, {SchemaID: id(2)}},
+			expected: id(2),
+		},
+		{
+			name:     "tie picks later event",
+			events:   []store.Event{{SchemaID: id(1)}, {SchemaID: id(2)}, {SchemaID: id(2)}, {SchemaID: id(1)}},
+			expected: id(1),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaID := seasonSchemaID(tt.events)
+
+			if !cmp.Equal(tt.expected, schemaID) {
+				t.Errorf("expected schema ID to equal expected, but got diff: %s", cmp.Diff(tt.expected, schemaID))
+			}
+		})
+	}
+}
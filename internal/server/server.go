@@ -8,6 +8,8 @@ import (
 	"github.com/NYTimes/gziphandler"
 	"github.com/npmanos/4176Gameday-backend/internal/config"
 	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/notify"
+	"github.com/npmanos/4176Gameday-backend/internal/refresh"
 	"github.com/npmanos/4176Gameday-backend/internal/store"
 	"github.com/npmanos/4176Gameday-backend/internal/tba"
 	"github.com/sirupsen/logrus"
@@ -20,23 +22,52 @@ var openAPI []byte
 type Server struct {
 	config.Server
 
-	TBA    *tba.Service
-	Store  *store.Service
-	Logger *logrus.Logger
-	start  time.Time
+	TBA       *tba.Service
+	Store     *store.Service
+	Broker    *notify.Broker
+	Logger    *logrus.Logger
+	Refresher *refresh.Service
+	start     time.Time
+
+	// TBAWebhookSecret is the secret TBA webhook messages are signed with. The webhook
+	// endpoint is only served when it is set.
+	TBAWebhookSecret string
 }
 
+// writeTimeout is the longest the server will spend writing a response, which also
+// limits how long an event stream can stay open.
+const writeTimeout = time.Second * 15
+
 func (s *Server) uptime() time.Duration {
 	return time.Since(s.start)
 }
 
+func (s *Server) nextPolls() map[string]time.Time {
+	if s.Refresher == nil {
+		return map[string]time.Time{}
+	}
+	return s.Refresher.NextPolls()
+}
+
 // Run starts the server, and returns if it runs into an error
 func (s *Server) Run(ctx context.Context) error {
 	router := s.registerRoutes()
 
+	// event streams aren't compressed since the gzip handler holds back writes until it
+	// has enough to compress
+	gzip, err := gziphandler.GzipHandlerWithOpts(gziphandler.ContentTypes([]string{
+		"application/json",
+		"application/x-yaml",
+		"text/plain",
+		"text/csv",
+	}))
+	if err != nil {
+		return err
+	}
+
 	var handler http.Handler = router
 	handler = ihttp.LimitBody(handler)
-	handler = gziphandler.GzipHandler(handler)
+	handler = gzip(handler)
 	handler = ihttp.Log(handler, s.Logger)
 	handler = ihttp.Auth(handler, s.JWTSecret)
 	handler = ihttp.CORS(handler, s.Origin)
@@ -46,7 +77,7 @@ func (s *Server) Run(ctx context.Context) error {
 		Handler:           handler,
 		ReadTimeout:       time.Second * 15,
 		ReadHeaderTimeout: time.Second * 15,
-		WriteTimeout:      time.Second * 15,
+		WriteTimeout:      writeTimeout,
 		IdleTimeout:       time.Second * 30,
 		MaxHeaderBytes:    4096,
 	}
@@ -1,8 +1,11 @@
 package server
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
 	"github.com/npmanos/4176Gameday-backend/internal/store"
@@ -10,7 +13,8 @@ import (
 	"github.com/gorilla/mux"
 )
 
-// eventStats analyzes the event-wide statistics of every team at an event with submitted reports
+// eventStats analyzes the event-wide statistics of every team at an event with submitted reports,
+// optionally only from each team's last N matches.
 func (s *Server) eventStats() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -22,62 +26,106 @@ func (s *Server) eventStats() http.HandlerFunc {
 			realmID = &userRealmID
 		}
 
-		event, err := s.Store.GetEventForRealm(r.Context(), eventKey, realmID)
+		var window int
+		if last := r.URL.Query().Get("last"); last != "" {
+			window, err = strconv.Atoi(last)
+			if err != nil || window < 1 {
+				ihttp.Error(w, http.StatusBadRequest)
+				return
+			}
+		}
+
+		_, summaries, err := s.summarizeEvent(r.Context(), eventKey, realmID, window)
 		if errors.Is(err, store.ErrNoResults{}) {
 			ihttp.Error(w, http.StatusNotFound)
 			return
+		} else if errors.Is(err, errNoSchema) {
+			ihttp.Respond(w, errNoSchema, http.StatusBadRequest)
+			return
 		} else if err != nil {
 			ihttp.Error(w, http.StatusInternalServerError)
-			s.Logger.WithError(err).Error("retrieving event")
+			s.Logger.WithError(err).Error("summarizing event")
 			return
 		}
 
-		if event.SchemaID == nil {
-			ihttp.Respond(w, errors.New("no schema found"), http.StatusBadRequest)
-			return
+		teamAnalyses := make([]teamAnalysis, 0)
+		for team, summary := range summaries {
+			teamAnalyses = append(teamAnalyses, teamAnalysisFromSummary(summary, team))
 		}
 
-		reports, err := s.Store.GetEventReportsForRealm(r.Context(), eventKey, realmID)
-		if err != nil {
-			ihttp.Error(w, http.StatusInternalServerError)
-			s.Logger.WithError(err).Error("retrieving reports")
-			return
-		}
+		ihttp.Respond(w, teamAnalyses, http.StatusOK)
+	}
+}
 
-		storeSchema, err := s.Store.GetSchemaByID(r.Context(), *event.SchemaID)
-		if errors.Is(err, store.ErrNoResults{}) {
-			ihttp.Error(w, http.StatusNotFound)
-			return
-		} else if err != nil {
-			ihttp.Error(w, http.StatusInternalServerError)
-			s.Logger.WithError(err).Error("retrieving event schema")
-			return
-		}
+// errNoSchema is returned when an event has no schema to summarize reports with.
+var errNoSchema = errors.New("no schema found")
 
-		storeMatches, err := s.Store.GetEventAnalysisInfoForRealm(r.Context(), eventKey, realmID)
-		if err != nil {
-			ihttp.Error(w, http.StatusInternalServerError)
-			s.Logger.WithError(err).Error("retrieving match analysis info")
-			return
-		}
+// eventTeamMatches retrieves the event schema and every team's matches at an event with all
+// reports visible to the given realm. It returns a store.ErrNoResults if the event or schema
+// do not exist, and errNoSchema if the event has no schema.
+func (s *Server) eventTeamMatches(ctx context.Context, eventKey string, realmID *int64) (store.Schema, map[string][]summary.Match, error) {
+	event, err := s.Store.GetEventForRealm(ctx, eventKey, realmID)
+	if err != nil {
+		return store.Schema{}, nil, fmt.Errorf("unable to retrieve event: %w", err)
+	}
 
-		schema := storeSummaryToSummarySchema(storeSchema)
-		teamToMatches := selectTeamMatches(storeMatches, reports)
+	if event.SchemaID == nil {
+		return store.Schema{}, nil, errNoSchema
+	}
 
-		teamAnalyses := make([]teamAnalysis, 0)
-		for team, teamToMatch := range teamToMatches {
-			summary, err := summary.SummarizeTeam(schema, teamToMatch)
-			if err != nil {
-				ihttp.Error(w, http.StatusInternalServerError)
-				s.Logger.WithError(err).WithField("team", team).Error("retrieving match summary")
-				return
-			}
+	storeSchema, err := s.Store.GetSchemaByID(ctx, *event.SchemaID)
+	if err != nil {
+		return store.Schema{}, nil, fmt.Errorf("unable to retrieve event schema: %w", err)
+	}
 
-			teamAnalyses = append(teamAnalyses, teamAnalysisFromSummary(summary, team))
+	teamMatches, err := s.teamMatchesForEvent(ctx, eventKey, realmID, storeSchema)
+	if err != nil {
+		return store.Schema{}, nil, err
+	}
+
+	return storeSchema, teamMatches, nil
+}
+
+// teamMatchesForEvent retrieves every team's matches at an event with all reports visible
+// to the given realm, regardless of the event's schema. Reports submitted against schema
+// are migrated to its latest version.
+func (s *Server) teamMatchesForEvent(ctx context.Context, eventKey string, realmID *int64, schema store.Schema) (map[string][]summary.Match, error) {
+	reports, err := s.Store.GetEventReportsForRealm(ctx, eventKey, realmID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve reports: %w", err)
+	}
+
+	storeMatches, err := s.Store.GetEventAnalysisInfoForRealm(ctx, eventKey, realmID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve match analysis info: %w", err)
+	}
+
+	return selectTeamMatches(storeMatches, reports, schema), nil
+}
+
+// summarizeEvent summarizes every team at an event using the event schema and all reports
+// visible to the given realm, and returns the schema with the summaries. If window is
+// positive, each stat is only summarized from a team's last window matches with a value for
+// it. Errors are the same as eventTeamMatches.
+func (s *Server) summarizeEvent(ctx context.Context, eventKey string, realmID *int64, window int) (store.Schema, map[string]summary.Summary, error) {
+	storeSchema, teamToMatches, err := s.eventTeamMatches(ctx, eventKey, realmID)
+	if err != nil {
+		return store.Schema{}, nil, err
+	}
+
+	schema := storeSummaryToSummarySchema(storeSchema)
+
+	summaries := make(map[string]summary.Summary)
+	for team, teamToMatch := range teamToMatches {
+		summary, err := summary.SummarizeTeamWindow(schema, teamToMatch, window)
+		if err != nil {
+			return store.Schema{}, nil, fmt.Errorf("unable to summarize team %s: %w", team, err)
 		}
 
-		ihttp.Respond(w, teamAnalyses, http.StatusOK)
+		summaries[team] = summary
 	}
+
+	return storeSchema, summaries, nil
 }
 
 func (s *Server) matchTeamStats() http.HandlerFunc {
@@ -136,7 +184,7 @@ func (s *Server) matchTeamStats() http.HandlerFunc {
 		}
 
 		schema := storeSummaryToSummarySchema(storeSchema)
-		teamToMatches := selectTeamMatches([]store.Match{match}, reports)
+		teamToMatches := selectTeamMatches([]store.Match{match}, reports, storeSchema)
 
 		summary, err := summary.SummarizeTeam(schema, teamToMatches[teamKey])
 		if err != nil {
@@ -150,7 +198,9 @@ func (s *Server) matchTeamStats() http.HandlerFunc {
 	}
 }
 
-func selectTeamMatches(storeMatches []store.Match, reports []store.Report) map[string][]summary.Match {
+func selectTeamMatches(storeMatches []store.Match, reports []store.Report, schema store.Schema) map[string][]summary.Match {
+	migrations := storeMigrationsToSummaryMigrations(schema)
+
 	teamToMatchToReports := make(map[string]map[string][]summary.Report)
 	for _, report := range reports {
 		var summaryReport summary.Report
@@ -161,6 +211,9 @@ func selectTeamMatches(storeMatches []store.Match, reports []store.Report) map[s
 				Value: stat.Value,
 			})
 		}
+		if report.HasSchema(schema.ID) {
+			summaryReport = summary.MigrateReport(summaryReport, report.SchemaVersion, migrations)
+		}
 
 		_, ok := teamToMatchToReports[report.TeamKey]
 		if !ok {
@@ -194,6 +247,20 @@ func selectTeamMatches(storeMatches []store.Match, reports []store.Report) map[s
 	return teamToMatches
 }
 
+func storeMigrationsToSummaryMigrations(storeSchema store.Schema) []summary.Migration {
+	migrations := make([]summary.Migration, 0, len(storeSchema.Migrations))
+
+	for _, migration := range storeSchema.Migrations {
+		migrations = append(migrations, summary.Migration{
+			Version: migration.Version,
+			Rename:  migration.Rename,
+			Scale:   migration.Scale,
+		})
+	}
+
+	return migrations
+}
+
 func storeSummaryToSummarySchema(storeSchema store.Schema) summary.Schema {
 	schema := make(summary.Schema, 0)
 
@@ -202,6 +269,7 @@ func storeSummaryToSummarySchema(storeSchema store.Schema) summary.Schema {
 			FieldDescriptor: summary.FieldDescriptor{Name: statDescription.FieldDescriptor.Name},
 			ReportReference: statDescription.ReportReference,
 			TBAReference:    statDescription.TBAReference,
+			Expression:      statDescription.Expression,
 		}
 
 		for _, v := range statDescription.Sum {
@@ -217,6 +285,7 @@ func storeSummaryToSummarySchema(storeSchema store.Schema) summary.Schema {
 
 		schema = append(schema, field)
 	}
+	schema.ParseExpressions()
 
 	return schema
 }
@@ -230,6 +299,11 @@ type summaryStat struct {
 	Name    string  `json:"name"`
 	Max     float64 `json:"max"`
 	Average float64 `json:"avg"`
+	Min     float64 `json:"min"`
+	Median  float64 `json:"median"`
+	StdDev  float64 `json:"stdDev"`
+	Trend   float64 `json:"trend"`
+	Count   int     `json:"count"`
 }
 
 func teamAnalysisFromSummary(summary summary.Summary, team string) teamAnalysis {
@@ -239,6 +313,11 @@ func teamAnalysisFromSummary(summary summary.Summary, team string) teamAnalysis
 			Name:    stat.Name,
 			Max:     stat.Max,
 			Average: stat.Average,
+			Min:     stat.Min,
+			Median:  stat.Median,
+			StdDev:  stat.StdDev,
+			Trend:   stat.Trend,
+			Count:   stat.Count,
 		})
 	}
 
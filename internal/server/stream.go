@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// streamDuration is how long an event stream stays open. The server's write timeout
+	// limits the length of a response, so streams end before it's reached and clients
+	// reconnect (EventSource does this automatically) with the Last-Event-ID header.
+	streamDuration = writeTimeout - time.Second*3
+	// streamRetry is how long clients should wait before reconnecting.
+	streamRetry = time.Second
+)
+
+// eventStreamHandler returns a handler that streams server-sent events whenever an
+// event's matches, rankings, or reports change. Messages only say what changed; clients
+// should fetch the data from the REST API. Report messages are only sent if the user
+// would be able to see the report.
+func (s *Server) eventStreamHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventKey := mux.Vars(r)["eventKey"]
+
+		var realmID *int64
+		userRealmID, err := ihttp.GetRealmID(r)
+		if err == nil {
+			realmID = &userRealmID
+		}
+
+		if _, err := s.Store.GetEventForRealm(r.Context(), eventKey, realmID); errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.Error("response writer does not support streaming")
+			return
+		}
+
+		lastID, _ := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64)
+
+		messages, unsubscribe := s.Broker.Subscribe(eventKey, lastID)
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetry/time.Millisecond)
+		flusher.Flush()
+
+		timer := time.NewTimer(streamDuration)
+		defer timer.Stop()
+
+		for {
+			select {
+			case msg := <-messages:
+				if !msg.VisibleTo(realmID) {
+					continue
+				}
+
+				data, err := json.Marshal(msg)
+				if err != nil {
+					s.Logger.WithError(err).Error("marshalling stream message")
+					return
+				}
+
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, data)
+				flusher.Flush()
+			case <-timer.C:
+				return
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/tba"
+)
+
+// maxWebhookBodySize is the largest webhook message accepted, well above the size of a
+// match score message with its full score breakdown.
+const maxWebhookBodySize = 1 << 18 // 256 KB
+
+// tbaWebhookHandler returns a handler for messages TBA sends to the server's webhook. The
+// body must be signed with the configured webhook secret in the X-TBA-HMAC header. Match,
+// schedule, and alliance messages update the store the same way the refresher does.
+func (s *Server) tbaWebhookHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+		if err != nil && len(body) >= maxWebhookBodySize {
+			ihttp.Error(w, http.StatusRequestEntityTooLarge)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusBadRequest)
+			return
+		}
+
+		if !tba.VerifyWebhook(s.TBAWebhookSecret, body, r.Header.Get("X-TBA-HMAC")) {
+			ihttp.Error(w, http.StatusUnauthorized)
+			return
+		}
+
+		m, err := tba.ParseWebhook(body)
+		if err != nil {
+			ihttp.Respond(w, err, http.StatusBadRequest)
+			return
+		}
+
+		logger := s.Logger.WithField("messageType", m.Type)
+
+		switch m.Type {
+		case tba.WebhookVerification:
+			key, err := m.VerificationKey()
+			if err != nil {
+				ihttp.Respond(w, err, http.StatusBadRequest)
+				return
+			}
+
+			logger.WithField("verificationKey", key).Info("received TBA webhook verification key")
+		case tba.WebhookMatchScore:
+			match, err := m.Match()
+			if err != nil {
+				ihttp.Respond(w, err, http.StatusBadRequest)
+				return
+			}
+
+			if err := s.Refresher.UpdateMatch(r.Context(), match); err != nil {
+				logger.WithError(err).Error("unable to update match from TBA webhook")
+				ihttp.Error(w, http.StatusInternalServerError)
+				return
+			}
+
+			if err := s.Refresher.RefreshRankings(r.Context(), match.EventKey); err != nil {
+				logger.WithError(err).Error("unable to refresh rankings from TBA webhook")
+				ihttp.Error(w, http.StatusInternalServerError)
+				return
+			}
+		case tba.WebhookUpcomingMatch, tba.WebhookScheduleUpdated:
+			eventKey, err := m.EventKey()
+			if err != nil {
+				ihttp.Respond(w, err, http.StatusBadRequest)
+				return
+			}
+
+			if err := s.Refresher.RefreshMatches(r.Context(), eventKey); err != nil {
+				logger.WithError(err).Error("unable to refresh matches from TBA webhook")
+				ihttp.Error(w, http.StatusInternalServerError)
+				return
+			}
+		case tba.WebhookAllianceSelection:
+			eventKey, err := m.EventKey()
+			if err != nil {
+				ihttp.Respond(w, err, http.StatusBadRequest)
+				return
+			}
+
+			if err := s.Refresher.RefreshAlliances(r.Context(), eventKey); err != nil {
+				logger.WithError(err).Error("unable to refresh alliances from TBA webhook")
+				ihttp.Error(w, http.StatusInternalServerError)
+				return
+			}
+		default:
+			// Pings and messages about things that aren't stored need no handling.
+			logger.Debug("ignoring TBA webhook message")
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTBAWebhookHandlerBodyLimit(t *testing.T) {
+	s := &Server{TBAWebhookSecret: "secret"}
+
+	testCases := []struct {
+		name     string
+		size     int
+		expected int
+	}{
+		{name: "unsigned message", size: 1024, expected: http.StatusUnauthorized},
+		{name: "too large", size: maxWebhookBodySize + 1, expected: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/tba/webhook", bytes.NewReader(make([]byte, tt.size)))
+
+			s.tbaWebhookHandler()(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Errorf("expected status %d, but got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+}
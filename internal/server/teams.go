@@ -29,7 +29,13 @@ func (s *Server) teamHandler() http.HandlerFunc {
 	}
 }
 
-// eventTeamHandler returns a handler to get a specific team at a specific event.
+type eventTeam struct {
+	store.EventTeam
+	Pit []store.PitReport `json:"pit"`
+}
+
+// eventTeamHandler returns a handler to get a specific team at a specific event, with its
+// pit reports from the user's realm and realms that share reports.
 func (s *Server) eventTeamHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -51,7 +57,14 @@ func (s *Server) eventTeamHandler() http.HandlerFunc {
 			return
 		}
 
-		ihttp.Respond(w, team, http.StatusOK)
+		pit, err := s.Store.GetEventTeamPitReportsForRealm(r.Context(), eventKey, teamKey, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving pit reports")
+			return
+		}
+
+		ihttp.Respond(w, eventTeam{EventTeam: team, Pit: pit}, http.StatusOK)
 	}
 }
 
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	ihttp "github.com/npmanos/4176Gameday-backend/internal/http"
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+	"github.com/gorilla/mux"
+)
+
+type statPoint struct {
+	MatchKey string     `json:"matchKey"`
+	Time     *time.Time `json:"time"`
+	Value    float64    `json:"value"`
+}
+
+type statSeries struct {
+	Name   string      `json:"name"`
+	Values []statPoint `json:"values"`
+}
+
+type teamTimeSeries struct {
+	Team  string       `json:"team"`
+	Stats []statSeries `json:"stats"`
+}
+
+// statTimeSeries finds the value of every schema field in each of a team's matches, using
+// the same per-match values that summaries are calculated from. teamMatches must be the
+// team's summary matches for storeMatches, in the same order. Matches without a value for
+// a field (e.g. unplayed matches) are left out of its series.
+func statTimeSeries(schema summary.Schema, storeMatches []store.Match, teamMatches []summary.Match) ([]statSeries, error) {
+	times := make(map[string]*time.Time)
+	for _, match := range storeMatches {
+		times[match.Key] = match.GetTime()
+	}
+
+	series := make([]statSeries, 0, len(schema))
+	indices := make(map[string]int)
+	for _, field := range schema {
+		indices[field.Name] = len(series)
+		series = append(series, statSeries{Name: field.Name, Values: make([]statPoint, 0)})
+	}
+
+	for _, match := range teamMatches {
+		values, err := summary.SummarizeMatch(schema, match)
+		if err != nil {
+			return nil, fmt.Errorf("unable to summarize match %s: %w", match.Key, err)
+		}
+
+		for _, field := range schema {
+			value, ok := values[field.Name]
+			if !ok {
+				continue
+			}
+
+			i := indices[field.Name]
+			series[i].Values = append(series[i].Values, statPoint{
+				MatchKey: match.Key,
+				Time:     times[match.Key],
+				Value:    value,
+			})
+		}
+	}
+
+	return series, nil
+}
+
+// teamStatTimeSeriesHandler returns a handler to get the value of every stat in each of a
+// team's matches at an event in chronological order, for graphing a team's progress.
+func (s *Server) teamStatTimeSeriesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		eventKey, teamKey := vars["eventKey"], vars["teamKey"]
+
+		var realmID *int64
+		userRealmID, err := ihttp.GetRealmID(r)
+		if err == nil {
+			realmID = &userRealmID
+		}
+
+		event, err := s.Store.GetEventForRealm(r.Context(), eventKey, realmID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event")
+			return
+		}
+
+		if event.SchemaID == nil {
+			ihttp.Respond(w, errNoSchema, http.StatusBadRequest)
+			return
+		}
+
+		storeSchema, err := s.Store.GetSchemaByID(r.Context(), *event.SchemaID)
+		if errors.Is(err, store.ErrNoResults{}) {
+			ihttp.Error(w, http.StatusNotFound)
+			return
+		} else if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving event schema")
+			return
+		}
+
+		// the same matches summaries are calculated from, already in chronological order
+		matches, err := s.Store.GetEventAnalysisInfoForRealm(r.Context(), eventKey, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving matches")
+			return
+		}
+
+		reports, err := s.Store.GetEventTeamReportsForRealm(r.Context(), eventKey, teamKey, realmID)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).Error("retrieving reports")
+			return
+		}
+
+		teamMatches := selectTeamMatches(matches, reports, storeSchema)[teamKey]
+
+		series, err := statTimeSeries(storeSummaryToSummarySchema(storeSchema), matches, teamMatches)
+		if err != nil {
+			ihttp.Error(w, http.StatusInternalServerError)
+			s.Logger.WithError(err).WithField("team", teamKey).Error("summarizing team matches")
+			return
+		}
+
+		ihttp.Respond(w, teamTimeSeries{Team: teamKey, Stats: series}, http.StatusOK)
+	}
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/npmanos/4176Gameday-backend/internal/summary"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStatTimeSeries(t *testing.T) {
+	schema := summary.Schema{
+		{FieldDescriptor: summary.FieldDescriptor{Name: "Cargo"}, ReportReference: "cargo"},
+		{FieldDescriptor: summary.FieldDescriptor{Name: "Endgame"}, TBAReference: "endgameRobot{{.RobotPosition}}"},
+		{FieldDescriptor: summary.FieldDescriptor{Name: "Hatches"}, ReportReference: "hatches"},
+	}
+
+	qm1Time := time.Date(2019, time.March, 1, 9, 0, 0, 0, time.UTC)
+	qm2Time := qm1Time.Add(time.Minute * 7)
+
+	storeMatches := []store.Match{
+		{Key: "qm1", ActualTime: &qm1Time},
+		{Key: "qm2", ScheduledTime: &qm2Time},
+		{Key: "qm3"},
+	}
+
+	report := func(cargo float64) summary.Report {
+		return summary.Report{{Name: "cargo", Value: cargo}}
+	}
+
+	teamMatches := []summary.Match{
+		{
+			Key:            "qm1",
+			RobotPosition:  2,
+			Reports:        []summary.Report{report(2), report(4)},
+			ScoreBreakdown: summary.ScoreBreakdown{"endgameRobot2": 12.0},
+		},
+		{Key: "qm2", RobotPosition: 1, Reports: []summary.Report{report(5)}},
+		{Key: "qm3", RobotPosition: 1},
+	}
+
+	expected := []statSeries{
+		{
+			Name: "Cargo",
+			Values: []statPoint{
+				{MatchKey: "qm1", Time: &qm1Time, Value: 3},
+				{MatchKey: "qm2", Time: &qm2Time, Value: 5},
+			},
+		},
+		{
+			Name:   "Endgame",
+			Values: []statPoint{{MatchKey: "qm1", Time: &qm1Time, Value: 12}},
+		},
+		{
+			Name: "Hatches",
+			Values: []statPoint{
+				{MatchKey: "qm1", Time: &qm1Time, Value: 0},
+				{MatchKey: "qm2", Time: &qm2Time, Value: 0},
+			},
+		},
+	}
+
+	series, err := statTimeSeries(schema, storeMatches, teamMatches)
+	if err != nil {
+		t.Fatalf("did not expect error but got: %v", err)
+	}
+
+	if !cmp.Equal(expected, series) {
+		t.Errorf("expected series to equal expected, but got diff: %s", cmp.Diff(expected, series))
+	}
+}
@@ -0,0 +1,353 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ErrInvalidSelection is returned when an alliance selection action breaks the
+// alliance selection rules (e.g. picking a team that is already on an alliance).
+type ErrInvalidSelection struct {
+	error
+}
+
+// Is returns whether the target is an ErrInvalidSelection.
+func (err ErrInvalidSelection) Is(target error) bool {
+	_, ok := target.(ErrInvalidSelection)
+	return ok
+}
+
+const (
+	numAlliances    = 8
+	selectionRounds = 2
+)
+
+// SelectionAlliances holds the team keys of each alliance, captain first, for storing
+// in one DB column.
+type SelectionAlliances [][]string
+
+// Value implements driver.Valuer to return JSON for the DB from SelectionAlliances.
+func (sa SelectionAlliances) Value() (driver.Value, error) { return json.Marshal(sa) }
+
+// Scan implements sql.Scanner to scan JSON from the DB into SelectionAlliances.
+func (sa *SelectionAlliances) Scan(src interface{}) error {
+	j, ok := src.([]byte)
+	if !ok {
+		return errors.New("got invalid type for SelectionAlliances")
+	}
+
+	return json.Unmarshal(j, sa)
+}
+
+// AllianceSelection holds the state of alliance selection at an event. Alliance
+// captains pick in serpentine order (1 through 8, then 8 through 1). Declined holds
+// the teams that have declined an invitation, and so may not be picked again. Each
+// realm tracks its own alliance selection; RealmID is nil for the alliances published
+// by TBA, which are shared by every realm.
+type AllianceSelection struct {
+	EventKey  string             `json:"eventKey" db:"event_key"`
+	RealmID   *int64             `json:"realmId,omitempty" db:"realm_id"`
+	Alliances SelectionAlliances `json:"alliances" db:"alliances"`
+	Declined  pq.StringArray     `json:"declined" db:"declined"`
+	FromTBA   bool               `json:"fromTba" db:"from_tba"`
+}
+
+// NewAllianceSelection creates an alliance selection with eight empty alliances.
+func NewAllianceSelection(eventKey string) AllianceSelection {
+	alliances := make(SelectionAlliances, numAlliances)
+	for i := range alliances {
+		alliances[i] = []string{}
+	}
+
+	return AllianceSelection{
+		EventKey:  eventKey,
+		Alliances: alliances,
+		Declined:  pq.StringArray{},
+	}
+}
+
+// Turn returns the one-indexed alliance whose captain picks next and the zero-indexed
+// round. done is true if every alliance has made all of its picks.
+func (as *AllianceSelection) Turn() (alliance, round int, done bool) {
+	var picks int
+	for _, teams := range as.Alliances {
+		if len(teams) > 1 {
+			picks += len(teams) - 1
+		}
+	}
+
+	if len(as.Alliances) == 0 || picks >= len(as.Alliances)*selectionRounds {
+		return 0, 0, true
+	}
+
+	round = picks / len(as.Alliances)
+	position := picks % len(as.Alliances)
+	if round%2 == 1 {
+		position = len(as.Alliances) - 1 - position
+	}
+
+	return position + 1, round, false
+}
+
+// Picked returns the set of teams that are on an alliance, including captains.
+func (as *AllianceSelection) Picked() map[string]bool {
+	picked := make(map[string]bool)
+	for _, teams := range as.Alliances {
+		for _, team := range teams {
+			picked[team] = true
+		}
+	}
+
+	return picked
+}
+
+func (as *AllianceSelection) allianceOf(team string) int {
+	for i, teams := range as.Alliances {
+		for _, t := range teams {
+			if t == team {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func (as *AllianceSelection) declined(team string) bool {
+	for _, t := range as.Declined {
+		if t == team {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetCaptain sets the captain of an empty alliance (one-indexed). Teams that declined
+// an invitation may still become captains.
+func (as *AllianceSelection) SetCaptain(alliance int, team string) error {
+	if alliance < 1 || alliance > len(as.Alliances) {
+		return ErrInvalidSelection{fmt.Errorf("alliance %d does not exist", alliance)}
+	}
+
+	if len(as.Alliances[alliance-1]) != 0 {
+		return ErrInvalidSelection{fmt.Errorf("alliance %d already has a captain", alliance)}
+	}
+
+	if i := as.allianceOf(team); i != -1 {
+		return ErrInvalidSelection{fmt.Errorf("team %s is already on alliance %d", team, i+1)}
+	}
+
+	as.Alliances[alliance-1] = []string{team}
+
+	return nil
+}
+
+// Pick adds a team to the alliance whose turn it is. A lower seeded captain may be
+// picked in the first round if their alliance has not picked yet, in which case the
+// alliances below it move up a seed.
+func (as *AllianceSelection) Pick(team string) error {
+	alliance, round, done := as.Turn()
+	if done {
+		return ErrInvalidSelection{errors.New("alliance selection is complete")}
+	}
+
+	current := alliance - 1
+	if len(as.Alliances[current]) == 0 {
+		return ErrInvalidSelection{fmt.Errorf("alliance %d has no captain", alliance)}
+	}
+
+	if as.Alliances[current][0] == team {
+		return ErrInvalidSelection{fmt.Errorf("captain %s cannot pick itself", team)}
+	}
+
+	if as.declined(team) {
+		return ErrInvalidSelection{fmt.Errorf("team %s declined an invitation and cannot be picked", team)}
+	}
+
+	if i := as.allianceOf(team); i != -1 {
+		if i <= current || round != 0 || len(as.Alliances[i]) != 1 {
+			return ErrInvalidSelection{fmt.Errorf("team %s is already on alliance %d", team, i+1)}
+		}
+
+		as.Alliances = append(as.Alliances[:i], as.Alliances[i+1:]...)
+		as.Alliances = append(as.Alliances, []string{})
+	}
+
+	as.Alliances[current] = append(as.Alliances[current], team)
+
+	return nil
+}
+
+// Decline records that a team declined an invitation from the alliance whose turn
+// it is. The turn does not advance.
+func (as *AllianceSelection) Decline(team string) error {
+	alliance, _, done := as.Turn()
+	if done {
+		return ErrInvalidSelection{errors.New("alliance selection is complete")}
+	}
+
+	if len(as.Alliances[alliance-1]) == 0 {
+		return ErrInvalidSelection{fmt.Errorf("alliance %d has no captain", alliance)}
+	}
+
+	if i := as.allianceOf(team); i != -1 {
+		return ErrInvalidSelection{fmt.Errorf("team %s is already on alliance %d", team, i+1)}
+	}
+
+	if as.declined(team) {
+		return ErrInvalidSelection{fmt.Errorf("team %s has already declined", team)}
+	}
+
+	as.Declined = append(as.Declined, team)
+
+	return nil
+}
+
+// GetAllianceSelection retrieves the alliance selection state of a realm for an event. If
+// the realm hasn't tracked alliance selection itself, the alliances published by TBA are
+// returned instead.
+func (s *Service) GetAllianceSelection(ctx context.Context, eventKey string, realmID int64) (AllianceSelection, error) {
+	var selection AllianceSelection
+
+	err := s.db.GetContext(ctx, &selection, `
+	SELECT *
+	FROM alliance_selections
+	WHERE
+		event_key = $1 AND
+		(realm_id = $2 OR realm_id IS NULL)
+	ORDER BY realm_id NULLS LAST
+	LIMIT 1`, eventKey, realmID)
+	if err == sql.ErrNoRows {
+		return selection, ErrNoResults{fmt.Errorf("no alliance selection for event %s: %w", eventKey, err)}
+	} else if err != nil {
+		return selection, fmt.Errorf("unable to retrieve alliance selection: %w", err)
+	}
+
+	return selection, nil
+}
+
+// EditAllianceSelection locks a realm's alliance selection for an event, calls editFunc
+// with it, and stores the result as a manual edit. If the realm has no alliance selection
+// yet, editFunc is called with the alliances published by TBA, or with empty alliances if
+// there are none. If editFunc returns an error nothing is stored.
+func (s *Service) EditAllianceSelection(ctx context.Context, eventKey string, realmID int64, editFunc func(*AllianceSelection) error) (AllianceSelection, error) {
+	selection := NewAllianceSelection(eventKey)
+
+	err := s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+		err := tx.GetContext(ctx, &selection, "SELECT * FROM alliance_selections WHERE event_key = $1 AND realm_id = $2 FOR UPDATE", eventKey, realmID)
+		if err == sql.ErrNoRows {
+			err = tx.GetContext(ctx, &selection, "SELECT * FROM alliance_selections WHERE event_key = $1 AND realm_id IS NULL", eventKey)
+		}
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("unable to retrieve alliance selection: %w", err)
+		}
+
+		if err := editFunc(&selection); err != nil {
+			return err
+		}
+		selection.RealmID = &realmID
+		selection.FromTBA = false
+
+		_, err = tx.NamedExecContext(ctx, `
+		INSERT INTO alliance_selections (event_key, realm_id, alliances, declined, from_tba)
+			VALUES (:event_key, :realm_id, :alliances, :declined, :from_tba)
+			ON CONFLICT (realm_id, event_key)
+			DO
+				UPDATE
+					SET
+						alliances = :alliances,
+						declined = :declined,
+						from_tba = :from_tba
+		`, selection)
+		if err != nil {
+			if err, ok := err.(*pq.Error); ok && err.Code == pgFKeyViolation {
+				return ErrFKeyViolation{fmt.Errorf("alliance selection fk violation: %w", err)}
+			}
+
+			return fmt.Errorf("unable to upsert alliance selection: %w", err)
+		}
+
+		return nil
+	})
+
+	return selection, err
+}
+
+// AllianceSelectionsUpsert stores alliance selection results from TBA. They are stored as
+// the event's shared alliance selection, and replace the alliances every realm tracked for
+// the event. The teams TBA lists as having declined are added to the teams each realm
+// recorded as having declined, so that a realm's own declines are kept.
+func (s *Service) AllianceSelectionsUpsert(ctx context.Context, selections []AllianceSelection) error {
+	return s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+		sharedStmt, err := tx.PrepareNamedContext(ctx, `
+		INSERT INTO alliance_selections (event_key, alliances, declined, from_tba)
+			VALUES (:event_key, :alliances, :declined, true)
+			ON CONFLICT (event_key) WHERE realm_id IS NULL
+			DO
+				UPDATE
+					SET
+						alliances = :alliances,
+						declined = :declined,
+						from_tba = true
+		`)
+		if err != nil {
+			return fmt.Errorf("unable to prepare alliance selection upsert statement: %w", err)
+		}
+		defer sharedStmt.Close()
+
+		realmsStmt, err := tx.PrepareNamedContext(ctx, `
+		UPDATE alliance_selections
+			SET
+				alliances = :alliances,
+				declined = ARRAY(
+					SELECT team
+					FROM unnest(declined || CAST(:declined AS TEXT[])) WITH ORDINALITY AS declines(team, i)
+					GROUP BY team
+					ORDER BY MIN(i)
+				),
+				from_tba = true
+			WHERE
+				event_key = :event_key AND
+				realm_id IS NOT NULL
+		`)
+		if err != nil {
+			return fmt.Errorf("unable to prepare realm alliance selection update statement: %w", err)
+		}
+		defer realmsStmt.Close()
+
+		for _, selection := range selections {
+			if _, err := sharedStmt.ExecContext(ctx, selection); err != nil {
+				return fmt.Errorf("unable to upsert alliance selection for event %s: %w", selection.EventKey, err)
+			}
+
+			if _, err := realmsStmt.ExecContext(ctx, selection); err != nil {
+				return fmt.Errorf("unable to update realm alliance selections for event %s: %w", selection.EventKey, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteAllianceSelection removes a realm's alliance selection state for an event. The
+// alliances published by TBA, if any, are left as they are.
+func (s *Service) DeleteAllianceSelection(ctx context.Context, eventKey string, realmID int64) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM alliance_selections WHERE event_key = $1 AND realm_id = $2", eventKey, realmID)
+	if err != nil {
+		return fmt.Errorf("unable to delete alliance selection: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNoResults{errors.New("got 0 affected rows")}
+	}
+
+	return nil
+}
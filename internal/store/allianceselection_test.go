@@ -0,0 +1,172 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type selectionAction struct {
+	kind     string
+	alliance int
+	team     string
+}
+
+func TestAllianceSelection(t *testing.T) {
+	captains := make([]selectionAction, 0)
+	for i, team := range []string{"frc1", "frc2", "frc3", "frc4", "frc5", "frc6", "frc7", "frc8"} {
+		captains = append(captains, selectionAction{kind: "captain", alliance: i + 1, team: team})
+	}
+
+	testCases := []struct {
+		name              string
+		actions           []selectionAction
+		expectErr         bool
+		expectedAlliances SelectionAlliances
+		expectedDeclined  []string
+		expectedAlliance  int
+		expectedRound     int
+		expectedDone      bool
+	}{
+		{
+			name:              "no captains",
+			actions:           nil,
+			expectedAlliances: SelectionAlliances{{}, {}, {}, {}, {}, {}, {}, {}},
+			expectedDeclined:  []string{},
+			expectedAlliance:  1,
+		},
+		{
+			name:      "pick without captain",
+			actions:   []selectionAction{{kind: "pick", team: "frc10"}},
+			expectErr: true,
+		},
+		{
+			name:      "captain picks itself",
+			actions:   append(captains[:1:1], selectionAction{kind: "pick", team: "frc1"}),
+			expectErr: true,
+		},
+		{
+			name:      "captain for alliance that does not exist",
+			actions:   []selectionAction{{kind: "captain", alliance: 9, team: "frc1"}},
+			expectErr: true,
+		},
+		{
+			name: "team already on an alliance becomes a captain",
+			actions: append(captains[:1:1],
+				selectionAction{kind: "pick", team: "frc10"},
+				selectionAction{kind: "captain", alliance: 2, team: "frc10"},
+			),
+			expectErr: true,
+		},
+		{
+			name: "declined team is picked",
+			actions: append(captains[:2:2],
+				selectionAction{kind: "decline", team: "frc10"},
+				selectionAction{kind: "pick", team: "frc11"},
+				selectionAction{kind: "pick", team: "frc10"},
+			),
+			expectErr: true,
+		},
+		{
+			name: "declined team becomes a captain",
+			actions: append(captains[:1:1],
+				selectionAction{kind: "decline", team: "frc10"},
+				selectionAction{kind: "pick", team: "frc11"},
+				selectionAction{kind: "captain", alliance: 2, team: "frc10"},
+			),
+			expectedAlliances: SelectionAlliances{{"frc1", "frc11"}, {"frc10"}, {}, {}, {}, {}, {}, {}},
+			expectedDeclined:  []string{"frc10"},
+			expectedAlliance:  2,
+		},
+		{
+			name: "picking a lower seeded captain moves alliances up",
+			actions: append(captains[:3:3],
+				selectionAction{kind: "pick", team: "frc2"},
+			),
+			expectedAlliances: SelectionAlliances{{"frc1", "frc2"}, {"frc3"}, {}, {}, {}, {}, {}, {}},
+			expectedDeclined:  []string{},
+			expectedAlliance:  2,
+		},
+		{
+			name: "picking a captain that has already picked",
+			actions: append(captains[:2:2],
+				selectionAction{kind: "pick", team: "frc10"},
+				selectionAction{kind: "pick", team: "frc1"},
+			),
+			expectErr: true,
+		},
+		{
+			name: "serpentine second round",
+			actions: append(captains[:8:8],
+				selectionAction{kind: "pick", team: "frc11"},
+				selectionAction{kind: "pick", team: "frc12"},
+				selectionAction{kind: "pick", team: "frc13"},
+				selectionAction{kind: "pick", team: "frc14"},
+				selectionAction{kind: "pick", team: "frc15"},
+				selectionAction{kind: "pick", team: "frc16"},
+				selectionAction{kind: "pick", team: "frc17"},
+				selectionAction{kind: "pick", team: "frc18"},
+				selectionAction{kind: "pick", team: "frc19"},
+			),
+			expectedAlliances: SelectionAlliances{
+				{"frc1", "frc11"},
+				{"frc2", "frc12"},
+				{"frc3", "frc13"},
+				{"frc4", "frc14"},
+				{"frc5", "frc15"},
+				{"frc6", "frc16"},
+				{"frc7", "frc17"},
+				{"frc8", "frc18", "frc19"},
+			},
+			expectedDeclined: []string{},
+			expectedAlliance: 7,
+			expectedRound:    1,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			selection := NewAllianceSelection("2019abca")
+
+			var err error
+			for _, action := range tt.actions {
+				switch action.kind {
+				case "captain":
+					err = selection.SetCaptain(action.alliance, action.team)
+				case "pick":
+					err = selection.Pick(action.team)
+				case "decline":
+					err = selection.Decline(action.team)
+				}
+
+				if err != nil {
+					break
+				}
+			}
+
+			if tt.expectErr {
+				if !errors.Is(err, ErrInvalidSelection{}) {
+					t.Errorf("expected invalid selection error but got: %v", err)
+				}
+				return
+			} else if err != nil {
+				t.Errorf("did not expect error but got: %v", err)
+				return
+			}
+
+			if !cmp.Equal(selection.Alliances, tt.expectedAlliances) {
+				t.Errorf("expected alliances to match, but got diff: %s", cmp.Diff(tt.expectedAlliances, selection.Alliances))
+			}
+
+			if !cmp.Equal([]string(selection.Declined), tt.expectedDeclined) {
+				t.Errorf("expected declined teams to match, but got diff: %s", cmp.Diff(tt.expectedDeclined, []string(selection.Declined)))
+			}
+
+			alliance, round, done := selection.Turn()
+			if alliance != tt.expectedAlliance || round != tt.expectedRound || done != tt.expectedDone {
+				t.Errorf("expected turn (%d, %d, %v) but got (%d, %d, %v)", tt.expectedAlliance, tt.expectedRound, tt.expectedDone, alliance, round, done)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ScoutScheduleOptions are the constraints used when assigning scouts to matches.
+type ScoutScheduleOptions struct {
+	// MaxConsecutive is the most matches in a row a scout will be assigned before
+	// getting a break. Zero means there is no limit.
+	MaxConsecutive int `json:"maxConsecutive" validate:"gte=0"`
+	// TeamKey is the realm's own team. No scouts are assigned during its matches,
+	// or during the TeamBreak matches before and after them.
+	TeamKey   string `json:"teamKey,omitempty"`
+	TeamBreak int    `json:"teamBreak" validate:"gte=0"`
+}
+
+// Value implements driver.Valuer to return JSON for the DB from ScoutScheduleOptions.
+func (so ScoutScheduleOptions) Value() (driver.Value, error) { return json.Marshal(so) }
+
+// Scan implements sql.Scanner to scan JSON from the DB into ScoutScheduleOptions.
+func (so *ScoutScheduleOptions) Scan(src interface{}) error {
+	j, ok := src.([]byte)
+	if !ok {
+		return errors.New("got invalid type for ScoutScheduleOptions")
+	}
+
+	return json.Unmarshal(j, so)
+}
+
+// ScoutSchedule holds the options a realm's scout assignments for an event were made
+// with. Fingerprint identifies the match schedule they were made from, so they can be
+// remade when it changes.
+type ScoutSchedule struct {
+	RealmID     int64                `json:"realmId" db:"realm_id"`
+	EventKey    string               `json:"eventKey" db:"event_key"`
+	Options     ScoutScheduleOptions `json:"options" db:"options"`
+	Fingerprint string               `json:"-" db:"fingerprint"`
+}
+
+// ScoutAssignment assigns a user to scout a team in a match.
+type ScoutAssignment struct {
+	RealmID  int64  `json:"-" db:"realm_id"`
+	EventKey string `json:"eventKey" db:"event_key"`
+	MatchKey string `json:"matchKey" db:"match_key"`
+	TeamKey  string `json:"teamKey" db:"team_key"`
+	UserID   int64  `json:"userId" db:"user_id"`
+}
+
+// GetScoutSchedulesForEvent retrieves every realm's scout schedule for an event.
+func (s *Service) GetScoutSchedulesForEvent(ctx context.Context, eventKey string) ([]ScoutSchedule, error) {
+	schedules := make([]ScoutSchedule, 0)
+
+	err := s.db.SelectContext(ctx, &schedules, "SELECT * FROM scout_schedules WHERE event_key = $1", eventKey)
+	if err != nil {
+		return schedules, fmt.Errorf("unable to retrieve scout schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// GetScoutSchedule retrieves a realm's scout schedule for an event.
+func (s *Service) GetScoutSchedule(ctx context.Context, eventKey string, realmID int64) (ScoutSchedule, error) {
+	var schedule ScoutSchedule
+
+	err := s.db.GetContext(ctx, &schedule, "SELECT * FROM scout_schedules WHERE event_key = $1 AND realm_id = $2", eventKey, realmID)
+	if err == sql.ErrNoRows {
+		return schedule, ErrNoResults{fmt.Errorf("no scout schedule for event %s: %w", eventKey, err)}
+	} else if err != nil {
+		return schedule, fmt.Errorf("unable to retrieve scout schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// SetScoutSchedule stores a realm's scout schedule for an event, replacing all of its
+// existing assignments.
+func (s *Service) SetScoutSchedule(ctx context.Context, schedule ScoutSchedule, assignments []ScoutAssignment) error {
+	return s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.NamedExecContext(ctx, `
+		INSERT INTO scout_schedules (realm_id, event_key, options, fingerprint)
+			VALUES (:realm_id, :event_key, :options, :fingerprint)
+			ON CONFLICT (realm_id, event_key)
+			DO
+				UPDATE
+					SET
+						options = :options,
+						fingerprint = :fingerprint
+		`, schedule)
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == pgFKeyViolation {
+			return ErrFKeyViolation{fmt.Errorf("scout schedule fk violation: %w", err)}
+		} else if err != nil {
+			return fmt.Errorf("unable to upsert scout schedule: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM scout_assignments WHERE realm_id = $1 AND event_key = $2", schedule.RealmID, schedule.EventKey)
+		if err != nil {
+			return fmt.Errorf("unable to delete scout assignments: %w", err)
+		}
+
+		stmt, err := tx.PrepareNamedContext(ctx, `
+		INSERT INTO scout_assignments (realm_id, event_key, match_key, team_key, user_id)
+			VALUES (:realm_id, :event_key, :match_key, :team_key, :user_id)
+		`)
+		if err != nil {
+			return fmt.Errorf("unable to prepare scout assignment insert statement: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, assignment := range assignments {
+			assignment.RealmID = schedule.RealmID
+			assignment.EventKey = schedule.EventKey
+
+			if _, err := stmt.ExecContext(ctx, assignment); err != nil {
+				return fmt.Errorf("unable to insert scout assignment: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetScoutAssignments retrieves a realm's scout assignments for an event, optionally only
+// for a single user.
+func (s *Service) GetScoutAssignments(ctx context.Context, eventKey string, realmID int64, userID *int64) ([]ScoutAssignment, error) {
+	assignments := make([]ScoutAssignment, 0)
+
+	err := s.db.SelectContext(ctx, &assignments, `
+	SELECT *
+	FROM scout_assignments
+	WHERE
+		event_key = $1 AND
+		realm_id = $2 AND
+		(user_id = $3 OR $3 IS NULL)
+	ORDER BY length(match_key), match_key, team_key
+	`, eventKey, realmID, userID)
+	if err != nil {
+		return assignments, fmt.Errorf("unable to retrieve scout assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// DeleteScoutSchedule removes a realm's scout schedule and assignments for an event.
+func (s *Service) DeleteScoutSchedule(ctx context.Context, eventKey string, realmID int64) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM scout_schedules WHERE event_key = $1 AND realm_id = $2", eventKey, realmID)
+	if err != nil {
+		return fmt.Errorf("unable to delete scout schedule: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNoResults{errors.New("got 0 affected rows")}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Comment is a note about a team at an event, optionally about a specific match. Comments
+// are either written on their own, or are the comment of a report (FromReport), which have
+// no ID and can only be changed through the report.
+type Comment struct {
+	ID         *int64    `json:"id,omitempty" db:"id"`
+	EventKey   string    `json:"-" db:"event_key"`
+	MatchKey   *string   `json:"matchKey,omitempty" db:"match_key"`
+	TeamKey    string    `json:"-" db:"team_key"`
+	ReporterID *int64    `json:"reporterId" db:"reporter_id"`
+	RealmID    *int64    `json:"-" db:"realm_id"`
+	Comment    string    `json:"comment" db:"comment" validate:"required"`
+	FromReport bool      `json:"fromReport" db:"from_report"`
+	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// CommentFilter limits which comments are retrieved. Nil or empty fields don't filter.
+// Search matches comments containing the text, ignoring case.
+type CommentFilter struct {
+	MatchKey   *string
+	ReporterID *int64
+	Search     string
+}
+
+// GetEventTeamCommentsForRealm retrieves the comments about a team at an event, including
+// report comments, oldest first. Only comments from realms that share reports or have a
+// matching realm ID are retrieved.
+func (s *Service) GetEventTeamCommentsForRealm(ctx context.Context, eventKey, teamKey string, filter CommentFilter, realmID *int64) ([]Comment, error) {
+	comments := make([]Comment, 0)
+
+	err := s.db.SelectContext(ctx, &comments, `
+	SELECT c.*
+	FROM (
+		SELECT id, event_key, match_key, team_key, reporter_id, realm_id, comment, false AS from_report, updated_at
+		FROM comments
+		UNION ALL
+		SELECT NULL, event_key, match_key, team_key, reporter_id, realm_id, comment, true, updated_at
+		FROM reports
+		WHERE comment IS NOT NULL AND comment != ''
+	) c
+	LEFT JOIN realms
+		ON realms.id = c.realm_id
+	WHERE
+		c.event_key = $1 AND
+		c.team_key = $2 AND
+		(c.realm_id IS NULL OR realms.share_reports = true OR realms.id = $3) AND
+		($4::TEXT IS NULL OR c.match_key = $4) AND
+		($5::INTEGER IS NULL OR c.reporter_id = $5) AND
+		c.comment ILIKE '%' || $6 || '%'
+	ORDER BY c.updated_at, c.id
+	`, eventKey, teamKey, realmID, filter.MatchKey, filter.ReporterID, escapeLike(filter.Search))
+	if err != nil {
+		return comments, fmt.Errorf("unable to retrieve comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike escapes text to be matched literally in a LIKE pattern.
+func escapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
+
+// CreateComment creates a new comment and returns its ID. It returns ErrFKeyViolation if
+// the team is not at the event or the match does not exist.
+func (s *Service) CreateComment(ctx context.Context, comment Comment) (int64, error) {
+	var id int64
+
+	stmt, err := s.db.PrepareNamedContext(ctx, `
+	INSERT INTO comments (event_key, match_key, team_key, reporter_id, realm_id, comment)
+		VALUES (:event_key, :match_key, :team_key, :reporter_id, :realm_id, :comment)
+		RETURNING id
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("unable to prepare comment insert statement: %w", err)
+	}
+	defer stmt.Close()
+
+	err = stmt.GetContext(ctx, &id, comment)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pgFKeyViolation {
+		return 0, ErrFKeyViolation{fmt.Errorf("comment fk violation: %w", err)}
+	} else if err != nil {
+		return 0, fmt.Errorf("unable to insert comment: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpdateComment replaces the text of a comment about a team at an event written by the
+// given reporter. If matchKey is not nil, the comment must be about that match. It returns
+// ErrNoResults if there is no such comment.
+func (s *Service) UpdateComment(ctx context.Context, eventKey string, matchKey *string, teamKey string, id, reporterID int64, comment string) error {
+	res, err := s.db.ExecContext(ctx, `
+	UPDATE comments
+	SET
+		comment = $6,
+		updated_at = now()
+	WHERE
+		event_key = $1 AND
+		($2::TEXT IS NULL OR match_key = $2) AND
+		team_key = $3 AND
+		id = $4 AND
+		reporter_id = $5
+	`, eventKey, matchKey, teamKey, id, reporterID, comment)
+	if err != nil {
+		return fmt.Errorf("unable to update comment: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("unable to determine rows affected: %w", err)
+	} else if n == 0 {
+		return ErrNoResults{fmt.Errorf("comment %d does not exist", id)}
+	}
+
+	return nil
+}
+
+// DeleteComment deletes a comment about a team at an event written by the given reporter.
+// If matchKey is not nil, the comment must be about that match. It returns ErrNoResults if
+// there is no such comment.
+func (s *Service) DeleteComment(ctx context.Context, eventKey string, matchKey *string, teamKey string, id, reporterID int64) error {
+	res, err := s.db.ExecContext(ctx, `
+	DELETE FROM comments
+	WHERE
+		event_key = $1 AND
+		($2::TEXT IS NULL OR match_key = $2) AND
+		team_key = $3 AND
+		id = $4 AND
+		reporter_id = $5
+	`, eventKey, matchKey, teamKey, id, reporterID)
+	if err != nil {
+		return fmt.Errorf("unable to delete comment: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("unable to determine rows affected: %w", err)
+	} else if n == 0 {
+		return ErrNoResults{fmt.Errorf("comment %d does not exist", id)}
+	}
+
+	return nil
+}
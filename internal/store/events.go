@@ -16,12 +16,14 @@ type Event struct {
 	Key          string         `json:"key" db:"key"`
 	RealmID      *int64         `json:"realmId,omitempty" db:"realm_id"`
 	SchemaID     *int64         `json:"schemaId,omitempty" db:"schema_id"`
+	PitSchemaID  *int64         `json:"pitSchemaId,omitempty" db:"pit_schema_id"`
 	Name         string         `json:"name" db:"name"`
 	District     *string        `json:"district,omitempty" db:"district"`
 	FullDistrict *string        `json:"fullDistrict,omitempty" db:"full_district"`
 	Week         *int           `json:"week,omitempty" db:"week"`
 	StartDate    time.Time      `json:"startDate" db:"start_date"`
 	EndDate      time.Time      `json:"endDate" db:"end_date"`
+	Timezone     string         `json:"timezone" db:"timezone"`
 	Webcasts     pq.StringArray `json:"webcasts" db:"webcasts"`
 	LocationName string         `json:"locationName" db:"location_name"`
 	GMapsURL     *string        `json:"gmapsUrl" db:"gmaps_url"`
@@ -30,8 +32,7 @@ type Event struct {
 	TBADeleted   bool           `json:"tbaDeleted" db:"tba_deleted"`
 }
 
-const eventsQuery = `
-SELECT
+const eventColumns = `
 	key,
 	name,
 	district,
@@ -39,20 +40,31 @@ SELECT
 	week,
 	start_date,
 	end_date,
+	timezone,
 	webcasts,
 	location_name,
 	gmaps_url,
 	lat,
 	lon,
 	tba_deleted,
-	events.realm_id,
-	COALESCE(schema_id, s.id) AS schema_id
+	events.realm_id,`
+
+const eventsQuery = `
+SELECT` + eventColumns + `
+	COALESCE(schema_id, s.id) AS schema_id,
+	ps.id AS pit_schema_id
 FROM
 	events
 LEFT JOIN
 	schemas s
 ON
-	s.year = EXTRACT(YEAR FROM start_date)
+	s.year = EXTRACT(YEAR FROM start_date) AND
+	s.kind = 'match'
+LEFT JOIN
+	schemas ps
+ON
+	ps.year = EXTRACT(YEAR FROM start_date) AND
+	ps.kind = 'pit'
 	`
 
 // GetEvents returns all events from the database. event.Webcasts and schemaID will be nil for every event.
@@ -69,7 +81,37 @@ func (s *Service) GetEvents(ctx context.Context, tbaDeleted bool, year *int) (ev
 	return events, s.db.SelectContext(ctx, &events, query, year)
 }
 
-const eventsRealmQuery = eventsQuery + `WHERE (events.realm_id IS NULL OR events.realm_id = $1)`
+// eventsRealmQuery is the same as eventsQuery, but prefers the realm's schema overrides for
+// an event over the event's own schema and the schemas for its year.
+const eventsRealmQuery = `
+SELECT` + eventColumns + `
+	COALESCE(es.schema_id, events.schema_id, s.id) AS schema_id,
+	COALESCE(eps.schema_id, ps.id) AS pit_schema_id
+FROM
+	events
+LEFT JOIN
+	event_schemas es
+ON
+	es.event_key = events.key AND
+	es.realm_id = $1 AND
+	es.kind = 'match'
+LEFT JOIN
+	event_schemas eps
+ON
+	eps.event_key = events.key AND
+	eps.realm_id = $1 AND
+	eps.kind = 'pit'
+LEFT JOIN
+	schemas s
+ON
+	s.year = EXTRACT(YEAR FROM start_date) AND
+	s.kind = 'match'
+LEFT JOIN
+	schemas ps
+ON
+	ps.year = EXTRACT(YEAR FROM start_date) AND
+	ps.kind = 'pit'
+WHERE (events.realm_id IS NULL OR events.realm_id = $1)`
 
 const eventRealmYearQuery = `
 SELECT DISTINCT
@@ -100,6 +142,24 @@ func (s *Service) GetEventYearsForRealm(ctx context.Context, realmID *int64) (ye
 	return years, s.db.SelectContext(ctx, &years, eventRealmYearQuery, realmID)
 }
 
+// GetTeamEventsForRealm returns the events a team attended in a year from GetEventsForRealm,
+// ordered by start date. Events that have been deleted from TBA are not returned.
+func (s *Service) GetTeamEventsForRealm(ctx context.Context, teamKey string, year int, realmID *int64) ([]Event, error) {
+	query := eventsRealmQuery + `
+	AND EXTRACT(YEAR FROM start_date) = $2
+	AND NOT tba_deleted
+	AND key IN (SELECT event_key FROM teams WHERE teams.key = $3)
+	ORDER BY start_date, key`
+
+	events := make([]Event, 0)
+	err := s.db.SelectContext(ctx, &events, query, realmID, year, teamKey)
+	if err != nil {
+		return events, fmt.Errorf("unable to retrieve team events: %w", err)
+	}
+
+	return events, nil
+}
+
 // GetEventForRealm retrieves a specific event in a specific realm (or no realm for TBA events).
 func (s *Service) GetEventForRealm(ctx context.Context, eventKey string, realmID *int64) (event Event, err error) {
 	err = s.db.GetContext(ctx, &event, eventsRealmQuery+" AND key = $2", realmID, eventKey)
@@ -110,11 +170,32 @@ func (s *Service) GetEventForRealm(ctx context.Context, eventKey string, realmID
 	return event, err
 }
 
-// GetActiveEvents returns all event keys for events that are currently happening.
-func (s *Service) GetActiveEvents(ctx context.Context) ([]string, error) {
-	const query = `SELECT key FROM events WHERE start_date <= CURRENT_DATE AND end_date >= CURRENT_DATE`
+// GetActiveEvents returns all event keys for events that are currently happening. An event
+// is happening on each day between its start and end dates in its own timezone. On days
+// with timed matches, it is only happening from buffer before the day's first match until
+// buffer after the day's last match.
+func (s *Service) GetActiveEvents(ctx context.Context, buffer time.Duration) ([]string, error) {
+	const query = `
+	SELECT events.key
+	FROM events
+	LEFT JOIN LATERAL (
+		SELECT
+			MIN(COALESCE(matches.actual_time, matches.predicted_time, matches.scheduled_time)) AS first_match,
+			MAX(COALESCE(matches.actual_time, matches.predicted_time, matches.scheduled_time)) AS last_match
+		FROM matches
+		WHERE
+			matches.event_key = events.key AND
+			NOT matches.tba_deleted AND
+			(COALESCE(matches.actual_time, matches.predicted_time, matches.scheduled_time) AT TIME ZONE events.timezone)::date = (now() AT TIME ZONE events.timezone)::date
+	) today ON true
+	WHERE
+		(events.start_date AT TIME ZONE events.timezone)::date <= (now() AT TIME ZONE events.timezone)::date AND
+		(events.end_date AT TIME ZONE events.timezone)::date >= (now() AT TIME ZONE events.timezone)::date AND
+		(today.first_match IS NULL OR now() BETWEEN today.first_match - $1 * INTERVAL '1 second' AND today.last_match + $1 * INTERVAL '1 second')
+	`
+
 	events := make([]string, 0)
-	return events, s.db.SelectContext(ctx, &events, query)
+	return events, s.db.SelectContext(ctx, &events, query, buffer.Seconds())
 }
 
 // EventsUpsert upserts multiple events into the database. It will set tba_deleted
@@ -122,8 +203,8 @@ func (s *Service) GetActiveEvents(ctx context.Context) ([]string, error) {
 func (s *Service) EventsUpsert(ctx context.Context, events []Event) error {
 	return s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
 		eventStmt, err := tx.PrepareNamedContext(ctx, `
-		INSERT INTO events (key, name, district, full_district, week, start_date, end_date, webcasts, location_name, gmaps_url, lat, lon, realm_id, schema_id, tba_deleted)
-		VALUES (:key, :name, :district, :full_district, :week, :start_date, :end_date, :webcasts, :location_name, :gmaps_url, :lat, :lon, :realm_id, :schema_id, :tba_deleted)
+		INSERT INTO events (key, name, district, full_district, week, start_date, end_date, timezone, webcasts, location_name, gmaps_url, lat, lon, realm_id, schema_id, tba_deleted)
+		VALUES (:key, :name, :district, :full_district, :week, :start_date, :end_date, :timezone, :webcasts, :location_name, :gmaps_url, :lat, :lon, :realm_id, :schema_id, :tba_deleted)
 		ON CONFLICT (key)
 		DO
 			UPDATE
@@ -134,6 +215,7 @@ func (s *Service) EventsUpsert(ctx context.Context, events []Event) error {
 					week = :week,
 					start_date = :start_date,
 					end_date = :end_date,
+					timezone = :timezone,
 					webcasts = :webcasts,
 					location_name = :location_name,
 					gmaps_url = :gmaps_url,
@@ -215,8 +297,8 @@ func (s *Service) GetEventRealmIDTx(ctx context.Context, tx *sqlx.Tx, eventKey s
 // the event was created or updated.
 func (s *Service) UpsertEventTx(ctx context.Context, tx *sqlx.Tx, event Event) error {
 	_, err := tx.NamedExecContext(ctx, `
-			INSERT INTO events (key, name, district, full_district, week, start_date, end_date, webcasts, location_name, gmaps_url, lat, lon, realm_id, schema_id, tba_deleted)
-				VALUES (:key, :name, :district, :full_district, :week, :start_date, :end_date, :webcasts, :location_name, :gmaps_url, :lat, :lon, :realm_id, :schema_id, :tba_deleted)
+			INSERT INTO events (key, name, district, full_district, week, start_date, end_date, timezone, webcasts, location_name, gmaps_url, lat, lon, realm_id, schema_id, tba_deleted)
+				VALUES (:key, :name, :district, :full_district, :week, :start_date, :end_date, :timezone, :webcasts, :location_name, :gmaps_url, :lat, :lon, :realm_id, :schema_id, :tba_deleted)
 			ON CONFLICT (key) DO
 				UPDATE
 					SET
@@ -226,6 +308,7 @@ func (s *Service) UpsertEventTx(ctx context.Context, tx *sqlx.Tx, event Event) e
 						week = :week,
 						start_date = :start_date,
 						end_date = :end_date,
+						timezone = :timezone,
 						webcasts = :webcasts,
 						location_name = :location_name,
 						gmaps_url= :gmaps_url,
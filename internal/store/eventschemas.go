@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// EventSchema overrides the schema of a kind a realm uses for an event, in place of the
+// event's own schema or the schema for its year.
+type EventSchema struct {
+	RealmID  int64  `json:"realmId" db:"realm_id"`
+	EventKey string `json:"eventKey" db:"event_key"`
+	Kind     string `json:"kind" db:"kind"`
+	SchemaID int64  `json:"schemaId" db:"schema_id"`
+}
+
+// SetEventSchema creates or replaces the schema of a kind a realm uses for an event.
+func (s *Service) SetEventSchema(ctx context.Context, eventSchema EventSchema) error {
+	_, err := s.db.NamedExecContext(ctx, `
+	INSERT INTO event_schemas (realm_id, event_key, kind, schema_id)
+		VALUES (:realm_id, :event_key, :kind, :schema_id)
+		ON CONFLICT (realm_id, event_key, kind)
+		DO
+			UPDATE
+				SET schema_id = :schema_id
+	`, eventSchema)
+	if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == pgFKeyViolation {
+		return ErrFKeyViolation{fmt.Errorf("event schema fk violation: %w", err)}
+	} else if err != nil {
+		return fmt.Errorf("unable to upsert event schema: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteEventSchema removes a realm's schema override of a kind for an event, so the realm
+// uses the event's own schema again. It returns ErrNoResults if the realm has no override.
+func (s *Service) DeleteEventSchema(ctx context.Context, eventKey string, realmID int64, kind string) error {
+	res, err := s.db.ExecContext(ctx, `
+	DELETE FROM event_schemas
+	WHERE
+		event_key = $1 AND
+		realm_id = $2 AND
+		kind = $3
+	`, eventKey, realmID, kind)
+	if err != nil {
+		return fmt.Errorf("unable to delete event schema: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("unable to determine rows affected: %w", err)
+	} else if n == 0 {
+		return ErrNoResults{fmt.Errorf("no %s schema override for event %s", kind, eventKey)}
+	}
+
+	return nil
+}
@@ -43,13 +43,13 @@ func (sb ScoreBreakdown) Value() (driver.Value, error) {
 
 // Scan unmarshals the JSON representation of the score breakdown stored in
 // the database into the score breakdown.
-func (sb ScoreBreakdown) Scan(src interface{}) error {
+func (sb *ScoreBreakdown) Scan(src interface{}) error {
 	j, ok := src.([]byte)
 	if !ok {
 		return errors.New("got invalid type for ScoreBreakdown")
 	}
 
-	return json.Unmarshal(j, &sb)
+	return json.Unmarshal(j, sb)
 }
 
 // GetTime returns the actual match time if available, and if not, predicted time
@@ -237,8 +237,34 @@ func (s *Service) MarkMatchesDeleted(ctx context.Context, eventKey string, match
 // into the database. New matches are added, existing matches will be updated,
 // and matches deleted from TBA will be deleted from the database. User-created
 // matches will be unaffected. It will set tba_deleted to false for all updated matches.
-func (s *Service) UpdateTBAMatches(ctx context.Context, matches []Match) error {
-	return s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+// scoresChanged is true if any match's red or blue score is different from what
+// was stored.
+func (s *Service) UpdateTBAMatches(ctx context.Context, matches []Match) (scoresChanged bool, err error) {
+	err = s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+		eventKeys := pq.StringArray{}
+		for _, match := range matches {
+			eventKeys = append(eventKeys, match.EventKey)
+		}
+
+		var stored []Match
+		err := tx.SelectContext(ctx, &stored, "SELECT key, event_key, red_score, blue_score FROM matches WHERE event_key = ANY($1)", eventKeys)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve stored match scores: %w", err)
+		}
+
+		storedMatches := make(map[[2]string]Match)
+		for _, match := range stored {
+			storedMatches[[2]string{match.EventKey, match.Key}] = match
+		}
+
+		for _, match := range matches {
+			storedMatch := storedMatches[[2]string{match.EventKey, match.Key}]
+			if !scoreEqual(storedMatch.RedScore, match.RedScore) || !scoreEqual(storedMatch.BlueScore, match.BlueScore) {
+				scoresChanged = true
+				break
+			}
+		}
+
 		upsert, err := tx.PrepareNamedContext(ctx, `
 		INSERT INTO matches (key, event_key, predicted_time, scheduled_time, actual_time, red_score, blue_score, tba_deleted, red_score_breakdown, blue_score_breakdown, tba_url, videos)
 		VALUES (:key, :event_key, :predicted_time, :scheduled_time, :actual_time, :red_score, :blue_score, :tba_deleted, :red_score_breakdown, :blue_score_breakdown, :tba_url, :videos)
@@ -273,11 +299,24 @@ func (s *Service) UpdateTBAMatches(ctx context.Context, matches []Match) error {
 
 		return nil
 	})
+
+	return scoresChanged && err == nil, err
+}
+
+func scoreEqual(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
 }
 
 const analysisInfoQuery = `
 SELECT
 	matches.key,
+	matches.predicted_time,
+	matches.scheduled_time,
+	matches.actual_time,
 	r.team_keys AS red_alliance,
 	b.team_keys AS blue_alliance,
 	matches.red_score_breakdown,
@@ -301,11 +340,17 @@ WHERE
 	matches.event_key = $2`
 
 // GetEventAnalysisInfoForRealm returns match information that's pertinent to doing analysis by getting
-// all the matches with the given event key and either null or matching realm IDs.
+// all the matches with the given event key and either null or matching realm IDs. Matches are ordered
+// by when they were played, or are expected to be played.
 func (s *Service) GetEventAnalysisInfoForRealm(ctx context.Context, eventKey string, realmID *int64) ([]Match, error) {
+	const query = analysisInfoQuery + `
+ORDER BY
+	COALESCE(matches.actual_time, matches.predicted_time, matches.scheduled_time),
+	matches.key`
+
 	matches := make([]Match, 0)
 
-	err := s.db.SelectContext(ctx, &matches, analysisInfoQuery, realmID, eventKey)
+	err := s.db.SelectContext(ctx, &matches, query, realmID, eventKey)
 	if err != nil {
 		return matches, fmt.Errorf("unable to get analysis info: %w", err)
 	}
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScoreBreakdownScan(t *testing.T) {
+	var sb ScoreBreakdown
+	if err := sb.Scan([]byte(`{"autoPoints": 15, "endgame": "Parked", "rocketComplete": true}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := ScoreBreakdown{"autoPoints": 15.0, "endgame": "Parked", "rocketComplete": true}
+	if !cmp.Equal(expected, sb) {
+		t.Errorf("expected scanned breakdown to equal expected, but got diff: %s", cmp.Diff(expected, sb))
+	}
+
+	if err := sb.Scan("not bytes"); err == nil {
+		t.Errorf("expected error scanning invalid type")
+	}
+}
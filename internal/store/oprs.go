@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// OPRComponents maps numeric score breakdown keys to a team's calculated
+// contribution to each of them, for storing in one DB column.
+type OPRComponents map[string]float64
+
+// Value implements driver.Valuer to return JSON for the DB from OPRComponents.
+func (oc OPRComponents) Value() (driver.Value, error) { return json.Marshal(oc) }
+
+// Scan implements sql.Scanner to scan JSON from the DB into OPRComponents.
+func (oc *OPRComponents) Scan(src interface{}) error {
+	j, ok := src.([]byte)
+	if !ok {
+		return errors.New("got invalid type for OPRComponents")
+	}
+
+	return json.Unmarshal(j, oc)
+}
+
+// TeamOPR holds a team's offensive power rating, defensive power rating, calculated
+// contribution to winning margin, and component OPRs at an event.
+type TeamOPR struct {
+	EventKey   string        `json:"eventKey" db:"event_key"`
+	TeamKey    string        `json:"teamKey" db:"team_key"`
+	OPR        float64       `json:"opr" db:"opr"`
+	DPR        float64       `json:"dpr" db:"dpr"`
+	CCWM       float64       `json:"ccwm" db:"ccwm"`
+	Components OPRComponents `json:"components" db:"components"`
+}
+
+// GetEventOPRs retrieves the calculated OPRs of all teams at an event, highest OPR first.
+func (s *Service) GetEventOPRs(ctx context.Context, eventKey string) ([]TeamOPR, error) {
+	oprs := make([]TeamOPR, 0)
+
+	err := s.db.SelectContext(ctx, &oprs, "SELECT * FROM event_oprs WHERE event_key = $1 ORDER BY opr DESC", eventKey)
+	if err != nil {
+		return oprs, fmt.Errorf("unable to retrieve event oprs: %w", err)
+	}
+
+	return oprs, nil
+}
+
+// SetEventOPRs replaces the calculated OPRs of all teams at an event.
+func (s *Service) SetEventOPRs(ctx context.Context, eventKey string, oprs []TeamOPR) error {
+	return s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM event_oprs WHERE event_key = $1", eventKey); err != nil {
+			return fmt.Errorf("unable to delete event oprs: %w", err)
+		}
+
+		stmt, err := tx.PrepareNamedContext(ctx, `
+		INSERT INTO event_oprs (event_key, team_key, opr, dpr, ccwm, components)
+		VALUES (:event_key, :team_key, :opr, :dpr, :ccwm, :components)
+		`)
+		if err != nil {
+			return fmt.Errorf("unable to prepare event oprs insert statement: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, opr := range oprs {
+			opr.EventKey = eventKey
+			if _, err := stmt.ExecContext(ctx, opr); err != nil {
+				return fmt.Errorf("unable to insert opr for team %s: %w", opr.TeamKey, err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// PickListTeam is a single entry in a pick list.
+type PickListTeam struct {
+	Team      string `json:"team"`
+	DoNotPick bool   `json:"doNotPick"`
+}
+
+// PickListTeams holds the ordered teams of a pick list for storing in one DB column.
+type PickListTeams []PickListTeam
+
+// Value implements driver.Valuer to return JSON for the DB from PickListTeams.
+func (pt PickListTeams) Value() (driver.Value, error) { return json.Marshal(pt) }
+
+// Scan implements sql.Scanner to scan JSON from the DB into PickListTeams.
+func (pt *PickListTeams) Scan(src interface{}) error {
+	j, ok := src.([]byte)
+	if !ok {
+		return errors.New("got invalid type for PickListTeams")
+	}
+
+	return json.Unmarshal(j, pt)
+}
+
+// PickList is a named, ordered list of teams a realm would like to pick during
+// alliance selection at a specific event.
+type PickList struct {
+	ID       int64         `json:"id" db:"id"`
+	EventKey string        `json:"eventKey" db:"event_key"`
+	RealmID  int64         `json:"realmId" db:"realm_id"`
+	Name     string        `json:"name" db:"name" validate:"gte=1,lte=64"`
+	Teams    PickListTeams `json:"teams" db:"teams"`
+}
+
+// GetPickListsForRealm retrieves all pick lists for an event that belong to the given realm.
+func (s *Service) GetPickListsForRealm(ctx context.Context, eventKey string, realmID int64) ([]PickList, error) {
+	pickLists := make([]PickList, 0)
+
+	err := s.db.SelectContext(ctx, &pickLists, `
+	SELECT *
+	FROM pick_lists
+	WHERE
+		event_key = $1 AND
+		realm_id = $2
+	ORDER BY id
+	`, eventKey, realmID)
+	if err != nil {
+		return pickLists, fmt.Errorf("unable to retrieve pick lists: %w", err)
+	}
+
+	return pickLists, nil
+}
+
+// GetPickListForRealm retrieves a specific pick list for an event that belongs to the given realm.
+func (s *Service) GetPickListForRealm(ctx context.Context, eventKey string, id, realmID int64) (PickList, error) {
+	var pickList PickList
+
+	err := s.db.GetContext(ctx, &pickList, `
+	SELECT *
+	FROM pick_lists
+	WHERE
+		event_key = $1 AND
+		id = $2 AND
+		realm_id = $3
+	`, eventKey, id, realmID)
+	if err == sql.ErrNoRows {
+		return pickList, ErrNoResults{fmt.Errorf("pick list %d does not exist: %w", id, err)}
+	} else if err != nil {
+		return pickList, fmt.Errorf("unable to retrieve pick list: %w", err)
+	}
+
+	return pickList, nil
+}
+
+// CreatePickList creates a new pick list and returns its ID.
+func (s *Service) CreatePickList(ctx context.Context, pickList PickList) (int64, error) {
+	var id int64
+
+	stmt, err := s.db.PrepareNamedContext(ctx, `
+	INSERT INTO pick_lists (event_key, realm_id, name, teams)
+		VALUES (:event_key, :realm_id, :name, :teams)
+		RETURNING id
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("unable to prepare pick list insert statement: %w", err)
+	}
+	defer stmt.Close()
+
+	err = stmt.GetContext(ctx, &id, pickList)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pgExists {
+		return 0, ErrExists{fmt.Errorf("pick list %q already exists: %w", pickList.Name, err)}
+	} else if ok && pqErr.Code == pgFKeyViolation {
+		return 0, ErrFKeyViolation{fmt.Errorf("pick list fk violation: %w", err)}
+	} else if err != nil {
+		return 0, fmt.Errorf("unable to insert pick list: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpdatePickList replaces the name and teams of a pick list belonging to the
+// pick list's realm.
+func (s *Service) UpdatePickList(ctx context.Context, pickList PickList) error {
+	res, err := s.db.NamedExecContext(ctx, `
+	UPDATE pick_lists
+		SET
+			name = :name,
+			teams = :teams
+		WHERE
+			id = :id AND
+			event_key = :event_key AND
+			realm_id = :realm_id
+	`, pickList)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pgExists {
+		return ErrExists{fmt.Errorf("pick list %q already exists: %w", pickList.Name, err)}
+	} else if err != nil {
+		return fmt.Errorf("unable to update pick list: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNoResults{fmt.Errorf("pick list %d does not exist", pickList.ID)}
+	}
+
+	return nil
+}
+
+// DeletePickList deletes a pick list belonging to the given realm.
+func (s *Service) DeletePickList(ctx context.Context, eventKey string, id, realmID int64) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM pick_lists WHERE event_key = $1 AND id = $2 AND realm_id = $3", eventKey, id, realmID)
+	if err != nil {
+		return fmt.Errorf("unable to delete pick list %d: %w", id, err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNoResults{errors.New("got 0 affected rows")}
+	}
+
+	return nil
+}
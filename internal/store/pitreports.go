@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// PitData holds the answers of a pit scouting report by pit schema field name. Values are
+// numbers, booleans, strings, or lists of strings.
+type PitData map[string]interface{}
+
+// Value implements driver.Valuer to return JSON for the DB from PitData.
+func (pd PitData) Value() (driver.Value, error) {
+	if pd == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(pd)
+}
+
+// Scan implements sql.Scanner to scan JSON from the DB into PitData.
+func (pd *PitData) Scan(src interface{}) error {
+	j, ok := src.([]byte)
+	if !ok {
+		return errors.New("got invalid type for PitData")
+	}
+
+	return json.Unmarshal(j, pd)
+}
+
+// PitReport is a realm's pit scouting report about a team at an event, such as its
+// drivetrain, weight, and auto routines. Photos are URLs of photos of the robot. Each
+// realm has at most one pit report for a team at an event.
+type PitReport struct {
+	ID         int64          `json:"-" db:"id"`
+	EventKey   string         `json:"-" db:"event_key"`
+	TeamKey    string         `json:"-" db:"team_key"`
+	RealmID    int64          `json:"realmId" db:"realm_id"`
+	ReporterID *int64         `json:"reporterId" db:"reporter_id"`
+	Data       PitData        `json:"data" db:"data"`
+	Photos     pq.StringArray `json:"photos" db:"photos" validate:"dive,url"`
+	UpdatedAt  time.Time      `json:"updatedAt" db:"updated_at"`
+}
+
+// UpsertPitReport creates a realm's pit report for a team at an event, or replaces it if
+// the realm already has one. It returns a boolean that is true when the report was created,
+// and false when it was updated. It returns ErrFKeyViolation if the team is not at the
+// event.
+func (s *Service) UpsertPitReport(ctx context.Context, r PitReport) (created bool, err error) {
+	if r.Photos == nil {
+		r.Photos = pq.StringArray{}
+	}
+
+	var existed bool
+
+	err = s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS(
+				SELECT FROM pit_reports
+				WHERE
+					event_key = $1 AND
+					team_key = $2 AND
+					realm_id = $3
+			)
+			`, r.EventKey, r.TeamKey, r.RealmID).Scan(&existed)
+		if err != nil {
+			return fmt.Errorf("unable to determine if pit report exists: %w", err)
+		}
+
+		_, err = tx.NamedExecContext(ctx, `
+			INSERT INTO
+				pit_reports (event_key, team_key, realm_id, reporter_id, data, photos)
+			VALUES (:event_key, :team_key, :realm_id, :reporter_id, :data, :photos)
+			ON CONFLICT (event_key, team_key, realm_id)
+				DO UPDATE SET
+					reporter_id = :reporter_id,
+					data = :data,
+					photos = :photos,
+					updated_at = now()
+		`, r)
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == pgFKeyViolation {
+			return ErrFKeyViolation{fmt.Errorf("pit report fk violation: %w", err)}
+		} else if err != nil {
+			return fmt.Errorf("unable to upsert pit report: %w", err)
+		}
+
+		return nil
+	})
+
+	return !existed, err
+}
+
+// GetEventTeamPitReportsForRealm retrieves the pit reports for a team at an event from the
+// given realm and from realms that share reports.
+func (s *Service) GetEventTeamPitReportsForRealm(ctx context.Context, eventKey, teamKey string, realmID *int64) ([]PitReport, error) {
+	reports := make([]PitReport, 0)
+
+	err := s.db.SelectContext(ctx, &reports, `
+	SELECT pit_reports.*
+	FROM pit_reports
+	INNER JOIN realms
+		ON realms.id = pit_reports.realm_id
+	WHERE
+		pit_reports.event_key = $1 AND
+		pit_reports.team_key = $2 AND
+		(realms.share_reports = true OR realms.id = $3)
+	ORDER BY pit_reports.updated_at DESC
+	`, eventKey, teamKey, realmID)
+	if err != nil {
+		return reports, fmt.Errorf("unable to retrieve pit reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// DeletePitReport deletes a realm's pit report for a team at an event. It returns
+// ErrNoResults if the realm has no pit report for the team.
+func (s *Service) DeletePitReport(ctx context.Context, eventKey, teamKey string, realmID int64) error {
+	res, err := s.db.ExecContext(ctx, `
+	DELETE FROM pit_reports
+	WHERE
+		event_key = $1 AND
+		team_key = $2 AND
+		realm_id = $3
+	`, eventKey, teamKey, realmID)
+	if err != nil {
+		return fmt.Errorf("unable to delete pit report: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("unable to determine rows affected: %w", err)
+	} else if n == 0 {
+		return ErrNoResults{fmt.Errorf("no pit report for team %s at event %s", teamKey, eventKey)}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// PointsStat marks the schema field a realm treats as a team's contribution to its
+// alliance's score when predicting matches.
+type PointsStat struct {
+	RealmID  int64  `json:"realmId" db:"realm_id"`
+	SchemaID int64  `json:"schemaId" db:"schema_id"`
+	Name     string `json:"name" db:"name"`
+}
+
+// GetPointsStat retrieves the points stat a realm has marked for a schema.
+func (s *Service) GetPointsStat(ctx context.Context, schemaID, realmID int64) (PointsStat, error) {
+	var pointsStat PointsStat
+
+	err := s.db.GetContext(ctx, &pointsStat, `
+	SELECT *
+	FROM points_stats
+	WHERE
+		schema_id = $1 AND
+		realm_id = $2
+	`, schemaID, realmID)
+	if err == sql.ErrNoRows {
+		return pointsStat, ErrNoResults{fmt.Errorf("no points stat for schema %d: %w", schemaID, err)}
+	} else if err != nil {
+		return pointsStat, fmt.Errorf("unable to retrieve points stat: %w", err)
+	}
+
+	return pointsStat, nil
+}
+
+// SetPointsStat creates or replaces the points stat a realm has marked for a schema.
+func (s *Service) SetPointsStat(ctx context.Context, pointsStat PointsStat) error {
+	_, err := s.db.NamedExecContext(ctx, `
+	INSERT INTO points_stats (realm_id, schema_id, name)
+		VALUES (:realm_id, :schema_id, :name)
+		ON CONFLICT (realm_id, schema_id)
+		DO
+			UPDATE
+				SET name = :name
+	`, pointsStat)
+	if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == pgFKeyViolation {
+		return ErrFKeyViolation{fmt.Errorf("points stat fk violation: %w", err)}
+	} else if err != nil {
+		return fmt.Errorf("unable to upsert points stat: %w", err)
+	}
+
+	return nil
+}
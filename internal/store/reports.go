@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -33,16 +34,41 @@ func (rd *ReportData) Scan(src interface{}) error {
 	return json.Unmarshal(j, rd)
 }
 
-// Report is data about how an FRC team performed in a specific match.
+// Report is data about how an FRC team performed in a specific match. ClientID and
+// UpdatedAt are set by the client that created the report when it is synced, and
+// RevisionTxID is the ID of the transaction that last stored the report. SchemaID and
+// SchemaVersion are the event schema and its version the report's data was submitted
+// against.
 type Report struct {
-	ID         int64      `json:"-" db:"id"`
-	EventKey   string     `json:"-" db:"event_key"`
-	MatchKey   string     `json:"-" db:"match_key"`
-	TeamKey    string     `json:"-" db:"team_key"`
-	ReporterID *int64     `json:"reporterId" db:"reporter_id"`
-	RealmID    *int64     `json:"-" db:"realm_id"`
-	Data       ReportData `json:"data" db:"data"`
-	Comment    string     `json:"comment" db:"comment"`
+	ID            int64      `json:"-" db:"id"`
+	EventKey      string     `json:"-" db:"event_key"`
+	MatchKey      string     `json:"-" db:"match_key"`
+	TeamKey       string     `json:"-" db:"team_key"`
+	ReporterID    *int64     `json:"reporterId" db:"reporter_id"`
+	RealmID       *int64     `json:"-" db:"realm_id"`
+	Data          ReportData `json:"data" db:"data"`
+	Comment       string     `json:"comment" db:"comment"`
+	ClientID      *string    `json:"-" db:"client_id"`
+	UpdatedAt     time.Time  `json:"-" db:"updated_at"`
+	RevisionTxID  int64      `json:"-" db:"revision_txid"`
+	SchemaID      *int64     `json:"schemaId" db:"schema_id"`
+	SchemaVersion int64      `json:"schemaVersion" db:"schema_version"`
+}
+
+// SetSchema sets the schema and version a report's data is submitted against, leaving the
+// schema unset for the empty schema of events that have none.
+func (r *Report) SetSchema(schema Schema) {
+	r.SchemaID = nil
+	if schema.ID != 0 {
+		r.SchemaID = &schema.ID
+	}
+	r.SchemaVersion = schema.Version
+}
+
+// HasSchema returns whether a report's data was submitted against the given schema, so its
+// version can be compared with the schema's versions and migrated by its migrations.
+func (r *Report) HasSchema(schemaID int64) bool {
+	return r.SchemaID != nil && *r.SchemaID == schemaID
 }
 
 // Leaderboard holds information about how many reports each reporter submitted.
@@ -51,6 +77,21 @@ type Leaderboard []struct {
 	Reports    int64 `json:"reports" db:"num_reports"`
 }
 
+const reportUpsert = `
+INSERT INTO
+	reports (event_key, match_key, team_key, reporter_id, realm_id, data, comment, schema_id, schema_version)
+VALUES (:event_key, :match_key, :team_key, :reporter_id, :realm_id, :data, :comment, :schema_id, :schema_version)
+ON CONFLICT (event_key, match_key, team_key, reporter_id)
+	DO UPDATE SET
+		data = :data,
+		realm_id = :realm_id,
+		comment = :comment,
+		schema_id = :schema_id,
+		schema_version = :schema_version,
+		updated_at = now(),
+		revision_txid = txid_current()
+`
+
 // UpsertReport creates a new report in the db, or replaces the existing one if
 // the same reporter already has a report in the db for that team and match. It
 // returns a boolean that is true when the report was created, and false when it
@@ -74,13 +115,7 @@ func (s *Service) UpsertReport(ctx context.Context, r Report) (created bool, err
 			return fmt.Errorf("unable to determine if report exists: %w", err)
 		}
 
-		_, err = tx.NamedExecContext(ctx, `
-			INSERT INTO
-				reports (event_key, match_key, team_key, reporter_id, realm_id, data, comment)
-			VALUES (:event_key, :match_key, :team_key, :reporter_id, :realm_id, :data, :comment)
-			ON CONFLICT (event_key, match_key, team_key, reporter_id)
-				DO UPDATE SET data = :data, realm_id = :realm_id, comment = :comment
-		`, r)
+		_, err = tx.NamedExecContext(ctx, reportUpsert, r)
 		if err != nil {
 			return fmt.Errorf("unable to upsert report: %w", err)
 		}
@@ -91,6 +126,39 @@ func (s *Service) UpsertReport(ctx context.Context, r Report) (created bool, err
 	return !existed, err
 }
 
+// errDryRun rolls back the transaction of a dry run import.
+var errDryRun = errors.New("dry run")
+
+// ImportReports upserts many reports in a single transaction, the same as UpsertReport, so
+// that either every report is stored or none are. If dryRun is true every report is
+// upserted, but the transaction is rolled back.
+func (s *Service) ImportReports(ctx context.Context, reports []Report, dryRun bool) error {
+	err := s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+		stmt, err := tx.PrepareNamedContext(ctx, reportUpsert)
+		if err != nil {
+			return fmt.Errorf("unable to prepare report upsert statement: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, r := range reports {
+			if _, err := stmt.ExecContext(ctx, r); err != nil {
+				return fmt.Errorf("unable to upsert report for team %s in match %s at event %s: %w", r.TeamKey, r.MatchKey, r.EventKey, err)
+			}
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
+	}
+
+	return err
+}
+
 // GetEventReportsForRealm returns all event reports for a specific event and realm.
 func (s *Service) GetEventReportsForRealm(ctx context.Context, eventKey string, realmID *int64) ([]Report, error) {
 	const query = `
@@ -100,7 +168,8 @@ func (s *Service) GetEventReportsForRealm(ctx context.Context, eventKey string,
 		ON realms.id = reports.realm_id AND
 		(realms.share_reports = true OR realms.id = $2)
 	WHERE
-		reports.event_key = $1`
+		reports.event_key = $1 AND
+		(reports.realm_id IS NULL OR realms.id IS NOT NULL)`
 
 	reports := []Report{}
 	return reports, s.db.SelectContext(ctx, &reports, query, eventKey, realmID)
@@ -117,7 +186,8 @@ func (s *Service) GetEventTeamReportsForRealm(ctx context.Context, eventKey stri
 		(realms.share_reports = true OR realms.id = $3)
 	WHERE
 		reports.event_key = $1 AND
-		reports.team_key = $2`
+		reports.team_key = $2 AND
+		(reports.realm_id IS NULL OR realms.id IS NOT NULL)`
 
 	reports = make([]Report, 0)
 	return reports, s.db.SelectContext(ctx, &reports, query, eventKey, teamKey, realmID)
@@ -135,12 +205,57 @@ func (s *Service) GetMatchTeamReportsForRealm(ctx context.Context, eventKey, mat
 	WHERE
 		reports.event_key = $1 AND
 		reports.match_key = $2 AND
-		reports.team_key = $3`
+		reports.team_key = $3 AND
+		(reports.realm_id IS NULL OR realms.id IS NOT NULL)`
 
 	reports = make([]Report, 0)
 	return reports, s.db.SelectContext(ctx, &reports, query, eventKey, matchKey, teamKey, realmID)
 }
 
+// MigrateSchemaReports rewrites every report that was submitted against the schema at a
+// version older than version. migrate is called with each report's data and schema
+// version, and returns the data for the new version. It returns the number of reports
+// that were rewritten.
+func (s *Service) MigrateSchemaReports(ctx context.Context, schemaID, version int64, migrate func(data ReportData, fromVersion int64) ReportData) (int64, error) {
+	var migrated int64
+
+	err := s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+		reports := []Report{}
+
+		err := tx.SelectContext(ctx, &reports, `
+		SELECT *
+		FROM reports
+		WHERE
+			schema_id = $1 AND
+			schema_version < $2
+		FOR UPDATE
+		`, schemaID, version)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve reports: %w", err)
+		}
+
+		for _, r := range reports {
+			_, err := tx.ExecContext(ctx, `
+			UPDATE reports
+			SET
+				data = $2,
+				schema_version = $3,
+				updated_at = now(),
+				revision_txid = txid_current()
+			WHERE id = $1
+			`, r.ID, migrate(r.Data, r.SchemaVersion), version)
+			if err != nil {
+				return fmt.Errorf("unable to update report %d: %w", r.ID, err)
+			}
+		}
+
+		migrated = int64(len(reports))
+		return nil
+	})
+
+	return migrated, err
+}
+
 // GetLeaderboardForRealm retrieves leaderboard information from the reports and users table for users
 // in the given realm. Specify year to filter for reports for events in the given year. Leave unspecified
 // for all years.
@@ -0,0 +1,203 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jmoiron/sqlx"
+)
+
+// testDatabaseURLEnv names the environment variable holding the URL of a PostgreSQL
+// database to run store tests against. Tests that need a database are skipped without it.
+const testDatabaseURLEnv = "PEREGRINE_TEST_DATABASE_URL"
+
+// newTestService creates a service for a new schema in the test database with every
+// migration applied. The returned function drops the schema.
+func newTestService(t *testing.T) (*Service, func()) {
+	t.Helper()
+
+	dsn := os.Getenv(testDatabaseURLEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+
+	db, err := sqlx.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("unable to open test database: %v", err)
+	}
+
+	// The search path is set per connection, so only ever use one.
+	db.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("store_test_%d", time.Now().UnixNano())
+	if _, err := db.Exec("CREATE SCHEMA " + schema); err != nil {
+		db.Close()
+		t.Fatalf("unable to create test schema: %v", err)
+	}
+
+	done := func() {
+		if _, err := db.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("unable to drop test schema: %v", err)
+		}
+		db.Close()
+	}
+
+	if _, err := db.Exec("SET search_path TO " + schema); err != nil {
+		done()
+		t.Fatalf("unable to set search path: %v", err)
+	}
+
+	if err := migrateUp(db); err != nil {
+		done()
+		t.Fatalf("unable to migrate test schema: %v", err)
+	}
+
+	return &Service{db: db}, done
+}
+
+// migrateUp runs every up migration in the migrations directory in order.
+func migrateUp(db *sqlx.DB) error {
+	files, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.up.sql"))
+	if err != nil {
+		return err
+	}
+
+	versions := make(map[string]int, len(files))
+	for _, f := range files {
+		v, err := strconv.Atoi(strings.SplitN(filepath.Base(f), "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("invalid migration name %s: %w", f, err)
+		}
+		versions[f] = v
+	}
+	sort.Slice(files, func(i, j int) bool { return versions[files[i]] < versions[files[j]] })
+
+	for _, f := range files {
+		migration, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.Exec(string(migration)); err != nil {
+			return fmt.Errorf("unable to run migration %s: %w", f, err)
+		}
+	}
+
+	return nil
+}
+
+// The realms join only matches realms sharing reports or the requesting realm, so reports
+// of other realms get NULL realm columns rather than being dropped by the join itself.
+func TestReportsForRealmVisibility(t *testing.T) {
+	s, done := newTestService(t)
+	defer done()
+
+	ctx := context.TODO()
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO events (key, name, start_date, end_date, location_name, lat, lon)
+		VALUES ('2019abca', 'Test Event', '2019-03-01', '2019-03-03', 'Test Venue', 0, 0)`); err != nil {
+		t.Fatalf("unable to insert event: %v", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO matches (key, event_key) VALUES ('qm1', '2019abca')`); err != nil {
+		t.Fatalf("unable to insert match: %v", err)
+	}
+
+	realms := make(map[string]*int64)
+	for _, realm := range []struct {
+		name  string
+		share bool
+	}{
+		{name: "own", share: false},
+		{name: "shared", share: true},
+		{name: "private", share: false},
+	} {
+		var id int64
+		if err := s.db.QueryRowContext(ctx, `INSERT INTO realms (name, share_reports) VALUES ($1, $2) RETURNING id`,
+			realm.name, realm.share).Scan(&id); err != nil {
+			t.Fatalf("unable to insert realm %s: %v", realm.name, err)
+		}
+		realms[realm.name] = &id
+	}
+
+	// Each report's comment names the realm that stored it, or none for reports whose
+	// realm was deleted.
+	for _, name := range []string{"none", "own", "shared", "private"} {
+		if _, err := s.db.ExecContext(ctx, `
+			INSERT INTO reports (event_key, match_key, team_key, realm_id, data, comment)
+			VALUES ('2019abca', 'qm1', 'frc2200', $1, '[]', $2)`, realms[name], name); err != nil {
+			t.Fatalf("unable to insert report of realm %s: %v", name, err)
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		realmID *int64
+		want    []string
+	}{
+		{
+			name:    "realm member",
+			realmID: realms["own"],
+			want:    []string{"none", "own", "shared"},
+		},
+		{
+			name:    "no realm",
+			realmID: nil,
+			want:    []string{"none", "shared"},
+		},
+	}
+
+	queries := []struct {
+		name  string
+		query func(realmID *int64) ([]Report, error)
+	}{
+		{
+			name: "event reports",
+			query: func(realmID *int64) ([]Report, error) {
+				return s.GetEventReportsForRealm(ctx, "2019abca", realmID)
+			},
+		},
+		{
+			name: "event team reports",
+			query: func(realmID *int64) ([]Report, error) {
+				return s.GetEventTeamReportsForRealm(ctx, "2019abca", "frc2200", realmID)
+			},
+		},
+		{
+			name: "match team reports",
+			query: func(realmID *int64) ([]Report, error) {
+				return s.GetMatchTeamReportsForRealm(ctx, "2019abca", "qm1", "frc2200", realmID)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		for _, q := range queries {
+			t.Run(tt.name+"/"+q.name, func(t *testing.T) {
+				reports, err := q.query(tt.realmID)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				got := make([]string, 0, len(reports))
+				for _, r := range reports {
+					got = append(got, r.Comment)
+				}
+				sort.Strings(got)
+
+				if !cmp.Equal(got, tt.want) {
+					t.Errorf("expected visible reports to equal expected, but got diff: %s", cmp.Diff(tt.want, got))
+				}
+			})
+		}
+	}
+}
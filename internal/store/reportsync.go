@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Report sync statuses.
+const (
+	ReportCreated  = "created"
+	ReportUpdated  = "updated"
+	ReportStale    = "stale"
+	ReportRejected = "rejected"
+)
+
+// ReportSyncResult is the outcome of syncing a single report.
+type ReportSyncResult struct {
+	ClientID string `json:"clientId"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// SyncReports upserts reports uploaded by a client in a single transaction. Each report
+// must have a ClientID and UpdatedAt set. A report is stale, and not stored, if the same
+// reporter's stored report for the match and team was updated after it. Reports that
+// can't be stored (e.g. for a match that doesn't exist) are rejected without affecting
+// the rest. The results are in the same order as the reports.
+func (s *Service) SyncReports(ctx context.Context, reports []Report) ([]ReportSyncResult, error) {
+	results := make([]ReportSyncResult, 0, len(reports))
+
+	err := s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+		results = results[:0]
+
+		for _, r := range reports {
+			result, err := s.syncReportTx(ctx, tx, r)
+			if err != nil {
+				return err
+			}
+
+			results = append(results, result)
+		}
+
+		return nil
+	})
+
+	return results, err
+}
+
+// syncReportTx stores a single report inside a savepoint so that a rejected report
+// doesn't abort the transaction.
+func (s *Service) syncReportTx(ctx context.Context, tx *sqlx.Tx, r Report) (ReportSyncResult, error) {
+	result := ReportSyncResult{ClientID: *r.ClientID}
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT sync_report"); err != nil {
+		return result, fmt.Errorf("unable to create savepoint: %w", err)
+	}
+
+	var existing Report
+	err := tx.GetContext(ctx, &existing, `
+	SELECT *
+	FROM reports
+	WHERE
+		event_key = $1 AND
+		match_key = $2 AND
+		team_key = $3 AND
+		reporter_id = $4
+	FOR UPDATE
+	`, r.EventKey, r.MatchKey, r.TeamKey, r.ReporterID)
+	if err != nil && err != sql.ErrNoRows {
+		return result, fmt.Errorf("unable to retrieve existing report: %w", err)
+	}
+
+	exists := err == nil
+	if exists && existing.UpdatedAt.After(r.UpdatedAt) {
+		result.Status = ReportStale
+		result.Reason = fmt.Sprintf("stored report was updated later at %s", existing.UpdatedAt.Format(time.RFC3339Nano))
+	} else {
+		result, err = s.upsertSyncedReportTx(ctx, tx, r, exists)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT sync_report"); err != nil {
+		return result, fmt.Errorf("unable to release savepoint: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *Service) upsertSyncedReportTx(ctx context.Context, tx *sqlx.Tx, r Report, exists bool) (ReportSyncResult, error) {
+	result := ReportSyncResult{ClientID: *r.ClientID}
+
+	_, err := tx.NamedExecContext(ctx, `
+		INSERT INTO
+			reports (event_key, match_key, team_key, reporter_id, realm_id, data, comment, client_id, updated_at, schema_id, schema_version)
+		VALUES (:event_key, :match_key, :team_key, :reporter_id, :realm_id, :data, :comment, :client_id, :updated_at, :schema_id, :schema_version)
+		ON CONFLICT (event_key, match_key, team_key, reporter_id)
+			DO UPDATE SET
+				data = :data,
+				realm_id = :realm_id,
+				comment = :comment,
+				schema_id = :schema_id,
+				schema_version = :schema_version,
+				client_id = :client_id,
+				updated_at = :updated_at,
+				revision_txid = txid_current()
+	`, r)
+	if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == pgFKeyViolation {
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT sync_report"); err != nil {
+			return result, fmt.Errorf("unable to roll back to savepoint: %w", err)
+		}
+
+		result.Status = ReportRejected
+		result.Reason = "match does not exist"
+		return result, nil
+	} else if err != nil {
+		return result, fmt.Errorf("unable to upsert report: %w", err)
+	}
+
+	if exists {
+		result.Status = ReportUpdated
+	} else {
+		result.Status = ReportCreated
+	}
+
+	return result, nil
+}
+
+// GetEventReportsSinceForRealm retrieves the reports for an event stored since the given
+// cursor, oldest first, that were not made by the given reporter, along with the cursor to
+// retrieve the reports stored after them. Only reports from realms that share reports or
+// have a matching realm ID are retrieved.
+//
+// Transaction IDs are assigned when a transaction starts, not when it commits, so the
+// largest one retrieved can't be used as a cursor. Instead the cursor is the oldest
+// transaction ID still in progress: only reports stored by transactions before it are retrieved, and those have
+// all committed, so every report is retrieved by a later call with the returned cursor.
+// Reports may be retrieved again if they are stored again.
+func (s *Service) GetEventReportsSinceForRealm(ctx context.Context, eventKey string, since, reporterID int64, realmID *int64) ([]Report, int64, error) {
+	reports := make([]Report, 0)
+
+	var cursor int64
+	if err := s.db.GetContext(ctx, &cursor, "SELECT txid_snapshot_xmin(txid_current_snapshot())"); err != nil {
+		return reports, since, fmt.Errorf("unable to retrieve oldest transaction in progress: %w", err)
+	}
+
+	err := s.db.SelectContext(ctx, &reports, `
+	SELECT reports.*
+	FROM reports
+	LEFT JOIN realms
+		ON realms.id = reports.realm_id
+	WHERE
+		reports.event_key = $1 AND
+		reports.revision_txid >= $2 AND
+		reports.revision_txid < $3 AND
+		reports.reporter_id IS DISTINCT FROM $4 AND
+		(reports.realm_id IS NULL OR realms.share_reports = true OR realms.id = $5)
+	ORDER BY reports.revision_txid, reports.id
+	`, eventKey, since, cursor, reporterID, realmID)
+	if err != nil {
+		return reports, since, fmt.Errorf("unable to retrieve reports: %w", err)
+	}
+
+	return reports, cursor, nil
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReportFieldError describes a report stat that doesn't match the event's schema.
+type ReportFieldError struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Reason string  `json:"reason"`
+}
+
+// ValidateReportData checks that every stat in a report is referenced by a schema field,
+// appears only once, and that stats for boolean fields are 0 or 1. A nil schema (for
+// events without one) accepts any report.
+func ValidateReportData(schema SchemaFields, data ReportData) []ReportFieldError {
+	errs := make([]ReportFieldError, 0)
+	if schema == nil {
+		return errs
+	}
+
+	fields := make(map[string]SchemaField)
+	for _, field := range schema {
+		if field.ReportReference != "" {
+			fields[field.ReportReference] = field
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, stat := range data {
+		field, ok := fields[stat.Name]
+
+		var reason string
+		switch {
+		case !ok:
+			reason = "not a field in the event's schema"
+		case seen[stat.Name]:
+			reason = "duplicate field"
+		case field.Type == "boolean" && stat.Value != 0 && stat.Value != 1:
+			reason = "expected boolean value of 0 or 1"
+		}
+		seen[stat.Name] = true
+
+		if reason != "" {
+			errs = append(errs, ReportFieldError{Name: stat.Name, Value: stat.Value, Reason: reason})
+		}
+	}
+
+	return errs
+}
+
+// ReportFieldErrorsReason describes report field errors in a single string, for places that
+// only have room for one reason a report is invalid.
+func ReportFieldErrorsReason(errs []ReportFieldError) string {
+	reasons := make([]string, 0, len(errs))
+	for _, err := range errs {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", err.Name, err.Reason))
+	}
+
+	return "report does not match schema: " + strings.Join(reasons, ", ")
+}
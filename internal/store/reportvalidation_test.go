@@ -0,0 +1,69 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateReportData(t *testing.T) {
+	schema := SchemaFields{
+		{FieldDescriptor: FieldDescriptor{Name: "Cargo"}, ReportReference: "cargo", Type: "number"},
+		{FieldDescriptor: FieldDescriptor{Name: "Crossed Line"}, ReportReference: "crossedLine", Type: "boolean"},
+		{FieldDescriptor: FieldDescriptor{Name: "Hatches"}, ReportReference: "hatches"},
+		{FieldDescriptor: FieldDescriptor{Name: "Rung"}, TBAReference: "rung"},
+	}
+
+	testCases := []struct {
+		name     string
+		schema   SchemaFields
+		data     ReportData
+		expected []ReportFieldError
+	}{
+		{
+			name:     "valid report",
+			schema:   schema,
+			data:     ReportData{{Name: "cargo", Value: 3.5}, {Name: "crossedLine", Value: 1}, {Name: "hatches", Value: -2}},
+			expected: []ReportFieldError{},
+		},
+		{
+			name:   "unknown field",
+			schema: schema,
+			data:   ReportData{{Name: "carg0", Value: 3}, {Name: "Cargo", Value: 3}, {Name: "rung", Value: 1}},
+			expected: []ReportFieldError{
+				{Name: "carg0", Value: 3, Reason: "not a field in the event's schema"},
+				{Name: "Cargo", Value: 3, Reason: "not a field in the event's schema"},
+				{Name: "rung", Value: 1, Reason: "not a field in the event's schema"},
+			},
+		},
+		{
+			name:   "duplicate field",
+			schema: schema,
+			data:   ReportData{{Name: "crossedLine", Value: 0}, {Name: "cargo", Value: 1}, {Name: "crossedLine", Value: 2}},
+			expected: []ReportFieldError{
+				{Name: "crossedLine", Value: 2, Reason: "duplicate field"},
+			},
+		},
+		{
+			name:     "boolean out of range",
+			schema:   schema,
+			data:     ReportData{{Name: "crossedLine", Value: 0.5}},
+			expected: []ReportFieldError{{Name: "crossedLine", Value: 0.5, Reason: "expected boolean value of 0 or 1"}},
+		},
+		{
+			name:     "no schema",
+			data:     ReportData{{Name: "cargo", Value: 1}},
+			expected: []ReportFieldError{},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateReportData(tt.schema, tt.data)
+
+			if !cmp.Equal(tt.expected, errs) {
+				t.Errorf("expected field errors to equal expected, but got diff: %s", cmp.Diff(tt.expected, errs))
+			}
+		})
+	}
+}
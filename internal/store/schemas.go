@@ -13,12 +13,17 @@ import (
 	"github.com/lib/pq"
 )
 
-// Schema describes the statistics that reports should include
+// Schema describes the statistics that reports should include. Version starts at 1 and
+// increases every time the schema fields change in a way that affects stored reports, with
+// a migration for each new version describing how to read reports from the previous one.
 type Schema struct {
-	ID      int64        `json:"id" db:"id"`
-	Year    *int64       `json:"year,omitempty" db:"year"`
-	RealmID *int64       `json:"realmId,omitempty" db:"realm_id"`
-	Schema  SchemaFields `json:"schema" db:"schema"`
+	ID         int64            `json:"id" db:"id"`
+	Year       *int64           `json:"year,omitempty" db:"year"`
+	RealmID    *int64           `json:"realmId,omitempty" db:"realm_id"`
+	Kind       string           `json:"kind" db:"kind"`
+	Schema     SchemaFields     `json:"schema" db:"schema"`
+	Version    int64            `json:"version" db:"version"`
+	Migrations SchemaMigrations `json:"migrations" db:"migrations"`
 }
 
 // FieldDescriptor defines properties of a schema field that aren't related to how it should be
@@ -28,13 +33,14 @@ type FieldDescriptor struct {
 }
 
 // SchemaField is a singular schema field. Only specify one of: ReportReference, TBAReference,
-// Sum, or AnyOf.
+// Sum, AnyOf, or Expression.
 type SchemaField struct {
 	FieldDescriptor
 	ReportReference string            `json:"reportReference,omitempty"`
 	TBAReference    string            `json:"tbaReference,omitempty"`
 	Sum             []FieldDescriptor `json:"sum,omitempty"`
 	AnyOf           []EqualExpression `json:"anyOf,omitempty"`
+	Expression      string            `json:"expression,omitempty"`
 
 	Hide   bool   `json:"hide,omitempty"`
 	Type   string `json:"type,omitempty"`
@@ -64,14 +70,51 @@ func (sd *SchemaFields) Scan(src interface{}) error {
 	return json.Unmarshal(j, sd)
 }
 
+// Schema kinds. Match schemas describe the stats in match reports and how to summarize them,
+// and pit schemas describe the fields of pit scouting reports.
+const (
+	SchemaKindMatch = "match"
+	SchemaKindPit   = "pit"
+)
+
+// SchemaMigration describes how to update report data from the previous version of a
+// schema to Version. Rename maps old report field names to new ones, and Scale multiplies
+// the value of a field (by its new name) after it is renamed.
+type SchemaMigration struct {
+	Version int64              `json:"version"`
+	Rename  map[string]string  `json:"rename,omitempty"`
+	Scale   map[string]float64 `json:"scale,omitempty"`
+}
+
+// SchemaMigrations holds every migration of a schema for storing in one DB column.
+type SchemaMigrations []SchemaMigration
+
+// Value implements driver.Valuer to return JSON for the DB from SchemaMigrations.
+func (sm SchemaMigrations) Value() (driver.Value, error) {
+	if sm == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(sm)
+}
+
+// Scan implements sql.Scanner to scan JSON from the DB into SchemaMigrations.
+func (sm *SchemaMigrations) Scan(src interface{}) error {
+	j, ok := src.([]byte)
+	if !ok {
+		return errors.New("got invalid type for SchemaMigrations")
+	}
+
+	return json.Unmarshal(j, sm)
+}
+
 // CreateSchema creates a new schema
 func (s *Service) CreateSchema(ctx context.Context, schema Schema) error {
 	return s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
 		_, err := tx.NamedExecContext(ctx, `
 		INSERT
 			INTO
-				schemas (year, realm_id, schema)
-			VALUES (:year, :realm_id, :schema)
+				schemas (year, realm_id, kind, schema)
+			VALUES (:year, :realm_id, :kind, :schema)
 		`, schema)
 
 		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == pgExists {
@@ -90,7 +133,7 @@ func (s *Service) GetSchemaByID(ctx context.Context, id int64) (Schema, error) {
 
 	err := s.db.GetContext(ctx, &schema, "SELECT * FROM schemas WHERE id = $1", id)
 	if err == sql.ErrNoRows {
-		return schema, ErrNoResults{fmt.Errorf("schema %d does not exist", schema.ID)}
+		return schema, ErrNoResults{fmt.Errorf("schema %d does not exist", id)}
 	} else if err != nil {
 		return schema, fmt.Errorf("unable to retrieve schema: %w", err)
 	}
@@ -98,11 +141,11 @@ func (s *Service) GetSchemaByID(ctx context.Context, id int64) (Schema, error) {
 	return schema, nil
 }
 
-// GetSchemaByYear retrieves the schema for a given year
+// GetSchemaByYear retrieves the match schema for a given year
 func (s *Service) GetSchemaByYear(ctx context.Context, year int) (Schema, error) {
 	var schema Schema
 
-	err := s.db.GetContext(ctx, &schema, "SELECT * FROM schemas WHERE year = $1", year)
+	err := s.db.GetContext(ctx, &schema, "SELECT * FROM schemas WHERE year = $1 AND kind = 'match'", year)
 	if err == sql.ErrNoRows {
 		return schema, ErrNoResults{fmt.Errorf("no schema for year %d exists", year)}
 	} else if err != nil {
@@ -134,3 +177,111 @@ func (s *Service) GetSchemasForRealm(ctx context.Context, realmID *int64) ([]Sch
 
 	return schemas, nil
 }
+
+// UpdateSchema updates the year and realm of a schema. Its fields are only changed by
+// AddSchemaVersion. It returns ErrNoResults if the schema does not exist, and ErrExists if
+// another schema of the same kind already exists for the year.
+func (s *Service) UpdateSchema(ctx context.Context, schema Schema) error {
+	res, err := s.db.NamedExecContext(ctx, `
+	UPDATE schemas
+	SET
+		year = :year,
+		realm_id = :realm_id
+	WHERE id = :id
+	`, schema)
+	if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == pgExists {
+		return &ErrExists{fmt.Errorf("schema already exists: %v", err.Error())}
+	} else if err != nil {
+		return fmt.Errorf("unable to update schema: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("unable to determine rows affected: %w", err)
+	} else if n == 0 {
+		return ErrNoResults{fmt.Errorf("schema %d does not exist", schema.ID)}
+	}
+
+	return nil
+}
+
+// DeleteSchema deletes a schema. Events that reference the schema are left without one,
+// falling back to the schema for their year, and realm overrides that use the schema are
+// removed. It returns ErrNoResults if the schema does not exist.
+func (s *Service) DeleteSchema(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM schemas WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("unable to delete schema: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("unable to determine rows affected: %w", err)
+	} else if n == 0 {
+		return ErrNoResults{fmt.Errorf("schema %d does not exist", id)}
+	}
+
+	return nil
+}
+
+// GetSchemaEventKeys retrieves the keys of events that use a schema by ID, either directly,
+// through a realm's override, or as the schema for their year. Pit schemas for a year are
+// used by every event of the year, and match schemas by the events without their own.
+func (s *Service) GetSchemaEventKeys(ctx context.Context, id int64) ([]string, error) {
+	keys := []string{}
+
+	err := s.db.SelectContext(ctx, &keys, `
+	SELECT key FROM events WHERE schema_id = $1
+	UNION
+	SELECT events.key
+	FROM events
+	INNER JOIN schemas
+		ON schemas.year = EXTRACT(YEAR FROM events.start_date)
+	WHERE
+		schemas.id = $1 AND
+		(schemas.kind = 'pit' OR events.schema_id IS NULL)
+	UNION
+	SELECT event_key FROM event_schemas WHERE schema_id = $1
+	ORDER BY key
+	`, id)
+	if err != nil {
+		return keys, fmt.Errorf("unable to retrieve schema events: %w", err)
+	}
+
+	return keys, nil
+}
+
+// AddSchemaVersion replaces the fields of a schema and increases its version, adding the
+// migration from the previous version. The migration's version is set to the new version.
+// It returns the updated schema, or ErrNoResults if the schema does not exist.
+func (s *Service) AddSchemaVersion(ctx context.Context, id int64, fields SchemaFields, migration SchemaMigration) (Schema, error) {
+	var schema Schema
+
+	err := s.DoTransaction(ctx, func(tx *sqlx.Tx) error {
+		err := tx.GetContext(ctx, &schema, "SELECT * FROM schemas WHERE id = $1 FOR UPDATE", id)
+		if err == sql.ErrNoRows {
+			return ErrNoResults{fmt.Errorf("schema %d does not exist", id)}
+		} else if err != nil {
+			return fmt.Errorf("unable to retrieve schema: %w", err)
+		}
+
+		migration.Version = schema.Version + 1
+		schema.Schema = fields
+		schema.Version = migration.Version
+		schema.Migrations = append(schema.Migrations, migration)
+
+		_, err = tx.NamedExecContext(ctx, `
+		UPDATE schemas
+		SET
+			schema = :schema,
+			version = :version,
+			migrations = :migrations
+		WHERE id = :id
+		`, schema)
+		if err != nil {
+			return fmt.Errorf("unable to update schema: %w", err)
+		}
+
+		return nil
+	})
+
+	return schema, err
+}
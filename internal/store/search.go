@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// SearchHit is a piece of text about a team that matched a search. Source is "report" for
+// report comments, "comment" for comments, and "team" for the team's nickname, which has no
+// event key. The snippet is the matching part of the text, HTML escaped, with matching words
+// wrapped in <b> tags.
+type SearchHit struct {
+	Source   string  `json:"source" db:"source"`
+	EventKey *string `json:"eventKey,omitempty" db:"event_key"`
+	MatchKey *string `json:"matchKey,omitempty" db:"match_key"`
+	Snippet  string  `json:"snippet" db:"snippet"`
+	Rank     float64 `json:"-" db:"rank"`
+}
+
+// TeamSearchResult holds the hits of a search about a single team.
+type TeamSearchResult struct {
+	TeamKey  string      `json:"teamKey"`
+	Nickname string      `json:"nickname"`
+	Hits     []SearchHit `json:"hits"`
+}
+
+// EventSearchResult is an event whose name matched a search.
+type EventSearchResult struct {
+	Key     string  `json:"key" db:"key"`
+	Name    string  `json:"name" db:"name"`
+	Snippet string  `json:"snippet" db:"snippet"`
+	Rank    float64 `json:"-" db:"rank"`
+}
+
+// SearchResults holds the teams and events matching a search, best matches first.
+type SearchResults struct {
+	Teams  []TeamSearchResult  `json:"teams"`
+	Events []EventSearchResult `json:"events"`
+}
+
+type teamSearchHit struct {
+	TeamKey  string `db:"team_key"`
+	Nickname string `db:"nickname"`
+	SearchHit
+}
+
+// Matches are marked in snippets with control characters, which are removed from the text
+// first, so that the text can be escaped before the marks are replaced with <b> tags.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// searchHighlightOptions marks matches in snippets with highlightStart and highlightStop.
+const searchHighlightOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `"`
+
+// searchHeadlineOptions also limits snippets of long comments to the fragments around matches.
+const searchHeadlineOptions = searchHighlightOptions + ", MaxFragments=2, MaxWords=20, MinWords=5"
+
+// highlightSnippet escapes a snippet from ts_headline with searchHighlightOptions as HTML, and
+// wraps its matches in <b> tags.
+func highlightSnippet(snippet string) string {
+	return strings.NewReplacer(highlightStart, "<b>", highlightStop, "</b>").Replace(html.EscapeString(snippet))
+}
+
+// Search finds report comments, comments, team nicknames, and event names matching the
+// words of a query using full-text search. Report comments and comments are only searched
+// from realms that share reports or have a matching realm ID, and events are only searched
+// with a null or matching realm ID. At most limit team hits and limit events are retrieved.
+func (s *Service) Search(ctx context.Context, query string, realmID *int64, limit int) (SearchResults, error) {
+	results := SearchResults{Teams: make([]TeamSearchResult, 0), Events: make([]EventSearchResult, 0)}
+
+	hits := make([]teamSearchHit, 0)
+	err := s.db.SelectContext(ctx, &hits, `
+	WITH q AS (SELECT plainto_tsquery('english', $1) AS query)
+	SELECT h.*, COALESCE(all_teams.nickname, '') AS nickname
+	FROM (
+		SELECT
+			reports.team_key,
+			'report' AS source,
+			reports.event_key,
+			reports.match_key,
+			ts_headline('english', translate(reports.comment, $5, ''), q.query, $4) AS snippet,
+			ts_rank(to_tsvector('english', reports.comment), q.query) AS rank
+		FROM reports
+		CROSS JOIN q
+		LEFT JOIN realms
+			ON realms.id = reports.realm_id
+		WHERE
+			to_tsvector('english', reports.comment) @@ q.query AND
+			(reports.realm_id IS NULL OR realms.share_reports = true OR realms.id = $2)
+		UNION ALL
+		SELECT
+			comments.team_key,
+			'comment',
+			comments.event_key,
+			comments.match_key,
+			ts_headline('english', translate(comments.comment, $5, ''), q.query, $4),
+			ts_rank(to_tsvector('english', comments.comment), q.query)
+		FROM comments
+		CROSS JOIN q
+		LEFT JOIN realms
+			ON realms.id = comments.realm_id
+		WHERE
+			to_tsvector('english', comments.comment) @@ q.query AND
+			(comments.realm_id IS NULL OR realms.share_reports = true OR realms.id = $2)
+		UNION ALL
+		SELECT
+			all_teams.key,
+			'team',
+			NULL,
+			NULL,
+			ts_headline('english', translate(all_teams.nickname, $5, ''), q.query, $6),
+			ts_rank(to_tsvector('english', coalesce(all_teams.nickname, '')), q.query)
+		FROM all_teams
+		CROSS JOIN q
+		WHERE to_tsvector('english', coalesce(all_teams.nickname, '')) @@ q.query
+	) h
+	LEFT JOIN all_teams
+		ON all_teams.key = h.team_key
+	ORDER BY h.rank DESC, h.team_key
+	LIMIT $3
+	`, query, realmID, limit, searchHeadlineOptions, highlightStart+highlightStop, searchHighlightOptions)
+	if err != nil {
+		return results, fmt.Errorf("unable to search teams: %w", err)
+	}
+
+	for i := range hits {
+		hits[i].Snippet = highlightSnippet(hits[i].Snippet)
+	}
+	results.Teams = groupTeamSearchHits(hits)
+
+	err = s.db.SelectContext(ctx, &results.Events, `
+	WITH q AS (SELECT plainto_tsquery('english', $1) AS query)
+	SELECT
+		events.key,
+		events.name,
+		ts_headline('english', translate(events.name, $4, ''), q.query, $5) AS snippet,
+		ts_rank(to_tsvector('english', events.name), q.query) AS rank
+	FROM events
+	CROSS JOIN q
+	WHERE
+		to_tsvector('english', events.name) @@ q.query AND
+		(events.realm_id IS NULL OR events.realm_id = $2)
+	ORDER BY rank DESC, events.start_date DESC
+	LIMIT $3
+	`, query, realmID, limit, highlightStart+highlightStop, searchHighlightOptions)
+	if err != nil {
+		return results, fmt.Errorf("unable to search events: %w", err)
+	}
+
+	for i := range results.Events {
+		results.Events[i].Snippet = highlightSnippet(results.Events[i].Snippet)
+	}
+
+	return results, nil
+}
+
+// groupTeamSearchHits groups hits by team, keeping the order of each team's first hit.
+func groupTeamSearchHits(hits []teamSearchHit) []TeamSearchResult {
+	results := make([]TeamSearchResult, 0)
+	indices := make(map[string]int)
+
+	for _, hit := range hits {
+		i, ok := indices[hit.TeamKey]
+		if !ok {
+			i = len(results)
+			indices[hit.TeamKey] = i
+			results = append(results, TeamSearchResult{TeamKey: hit.TeamKey, Nickname: hit.Nickname})
+		}
+
+		results[i].Hits = append(results[i].Hits, hit.SearchHit)
+	}
+
+	return results
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGroupTeamSearchHits(t *testing.T) {
+	eventKey := "2019flor"
+
+	testCases := []struct {
+		name     string
+		hits     []teamSearchHit
+		expected []TeamSearchResult
+	}{
+		{
+			name:     "no hits",
+			expected: []TeamSearchResult{},
+		},
+		{
+			name: "grouped by team in order of first hit",
+			hits: []teamSearchHit{
+				{TeamKey: "frc4176", Nickname: "Rams", SearchHit: SearchHit{Source: "report", EventKey: &eventKey, Snippet: "great <b>defense</b>"}},
+				{TeamKey: "frc254", Nickname: "The Cheesy Poofs", SearchHit: SearchHit{Source: "comment", EventKey: &eventKey, Snippet: "<b>defense</b> bot"}},
+				{TeamKey: "frc4176", Nickname: "Rams", SearchHit: SearchHit{Source: "comment", EventKey: &eventKey, Snippet: "played <b>defense</b>"}},
+			},
+			expected: []TeamSearchResult{
+				{
+					TeamKey:  "frc4176",
+					Nickname: "Rams",
+					Hits: []SearchHit{
+						{Source: "report", EventKey: &eventKey, Snippet: "great <b>defense</b>"},
+						{Source: "comment", EventKey: &eventKey, Snippet: "played <b>defense</b>"},
+					},
+				},
+				{
+					TeamKey:  "frc254",
+					Nickname: "The Cheesy Poofs",
+					Hits: []SearchHit{
+						{Source: "comment", EventKey: &eventKey, Snippet: "<b>defense</b> bot"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			results := groupTeamSearchHits(tt.hits)
+
+			if !cmp.Equal(tt.expected, results) {
+				t.Errorf("expected results to equal expected, but got diff: %s", cmp.Diff(tt.expected, results))
+			}
+		})
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	testCases := []struct {
+		name     string
+		snippet  string
+		expected string
+	}{
+		{
+			name:     "matches",
+			snippet:  "played great \x02defense\x03 all \x02match\x03",
+			expected: "played great <b>defense</b> all <b>match</b>",
+		},
+		{
+			name:     "html in text",
+			snippet:  "<script>alert('x')</script> & \x02<b>defense</b>\x03",
+			expected: "&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt; &amp; <b>&lt;b&gt;defense&lt;/b&gt;</b>",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if snippet := highlightSnippet(tt.snippet); snippet != tt.expected {
+				t.Errorf("expected snippet %q, but got %q", tt.expected, snippet)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package summary
+
+import "errors"
+
+// UndefinedReference is a reference from a Sum, AnyOf, or Expression field to a field that
+// isn't defined before it in the schema, so it never has a value.
+type UndefinedReference struct {
+	Field     string
+	Reference string
+}
+
+// InvalidField is a schema field that can't be summarized at all, such as an expression
+// that can't be parsed.
+type InvalidField struct {
+	Field string
+	Err   error
+}
+
+// Diagnostics describes problems with a schema found by summarizing matches with it.
+type Diagnostics struct {
+	// UnresolvedReportReferences are the names of ReportReference fields whose reference
+	// isn't in any report.
+	UnresolvedReportReferences []string
+	// UnmatchedTBAReferences are the names of TBAReference fields whose template never
+	// matches a score breakdown key.
+	UnmatchedTBAReferences []string
+	UndefinedReferences    []UndefinedReference
+	InvalidFields          []InvalidField
+}
+
+// Diagnose checks a schema against matches (for any teams) for fields that will never have
+// a value or that can't be summarized. A schema with InvalidFields can't be used to
+// summarize the matches.
+func Diagnose(schema Schema, matches []Match) Diagnostics {
+	diagnostics := Diagnostics{
+		UnresolvedReportReferences: make([]string, 0),
+		UnmatchedTBAReferences:     make([]string, 0),
+		UndefinedReferences:        make([]UndefinedReference, 0),
+		InvalidFields:              make([]InvalidField, 0),
+	}
+
+	reportNames := make(map[string]bool)
+	for _, match := range matches {
+		for _, report := range match.Reports {
+			for _, field := range report {
+				reportNames[field.Name] = true
+			}
+		}
+	}
+
+	defined := make(map[string]bool)
+	checkRefs := func(field SchemaField, refs []string) {
+		for _, ref := range refs {
+			if !defined[ref] {
+				diagnostics.UndefinedReferences = append(diagnostics.UndefinedReferences, UndefinedReference{
+					Field:     field.Name,
+					Reference: ref,
+				})
+			}
+		}
+	}
+
+	for _, field := range schema {
+		switch {
+		case field.ReportReference != "":
+			if !reportNames[field.ReportReference] {
+				diagnostics.UnresolvedReportReferences = append(diagnostics.UnresolvedReportReferences, field.Name)
+			}
+		case field.TBAReference != "":
+			matched, err := tbaReferenceMatches(field.TBAReference, matches)
+			if err != nil {
+				diagnostics.InvalidFields = append(diagnostics.InvalidFields, InvalidField{Field: field.Name, Err: err})
+			} else if !matched {
+				diagnostics.UnmatchedTBAReferences = append(diagnostics.UnmatchedTBAReferences, field.Name)
+			}
+		case len(field.Sum) != 0:
+			refs := make([]string, 0, len(field.Sum))
+			for _, ref := range field.Sum {
+				refs = append(refs, ref.Name)
+			}
+			checkRefs(field, refs)
+		case len(field.AnyOf) != 0:
+			refs := make([]string, 0, len(field.AnyOf))
+			for _, ref := range field.AnyOf {
+				refs = append(refs, ref.Name)
+			}
+			checkRefs(field, refs)
+		case field.Expression != "":
+			expr, err := ParseExpression(field.Expression)
+			if err != nil {
+				diagnostics.InvalidFields = append(diagnostics.InvalidFields, InvalidField{Field: field.Name, Err: err})
+			} else {
+				checkRefs(field, expr.References())
+			}
+		default:
+			diagnostics.InvalidFields = append(diagnostics.InvalidFields, InvalidField{
+				Field: field.Name,
+				Err:   errors.New("no ReportReference, TBAReference, Sum, AnyOf, or Expression"),
+			})
+		}
+
+		defined[field.Name] = true
+	}
+
+	return diagnostics
+}
+
+// tbaReferenceMatches returns whether a TBA reference template matches a score breakdown
+// key in any of the matches.
+func tbaReferenceMatches(tbaReference string, matches []Match) (bool, error) {
+	if _, err := tbaKey(tbaReference, 1); err != nil {
+		return false, err
+	}
+
+	for _, match := range matches {
+		key, err := tbaKey(tbaReference, match.RobotPosition)
+		if err != nil {
+			return false, err
+		}
+
+		if _, ok := match.ScoreBreakdown[key]; ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
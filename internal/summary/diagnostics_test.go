@@ -0,0 +1,98 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestDiagnose(t *testing.T) {
+	matches := []Match{
+		{
+			Key:            "qm1",
+			Reports:        []Report{{{Name: "made", Value: 2}}},
+			RobotPosition:  2,
+			ScoreBreakdown: ScoreBreakdown{"endgameRobot2": "HabLevel1"},
+		},
+		{
+			Key:           "qm2",
+			Reports:       []Report{{{Name: "missed", Value: 1}}},
+			RobotPosition: 1,
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		schema   Schema
+		expected Diagnostics
+	}{
+		{
+			name: "valid",
+			schema: Schema{
+				{FieldDescriptor: FieldDescriptor{Name: "Made"}, ReportReference: "made"},
+				{FieldDescriptor: FieldDescriptor{Name: "Missed"}, ReportReference: "missed"},
+				{FieldDescriptor: FieldDescriptor{Name: "endgame"}, TBAReference: "endgameRobot{{.RobotPosition}}"},
+				{FieldDescriptor: FieldDescriptor{Name: "Total"}, Sum: []FieldDescriptor{{Name: "Made"}, {Name: "Missed"}}},
+				{FieldDescriptor: FieldDescriptor{Name: "Climbed"}, AnyOf: []EqualExpression{{FieldDescriptor: FieldDescriptor{Name: "endgame"}, Equals: "HabLevel1"}}},
+				{FieldDescriptor: FieldDescriptor{Name: "Accuracy"}, Expression: "Made / Total"},
+			},
+		},
+		{
+			name: "unresolved references",
+			schema: Schema{
+				{FieldDescriptor: FieldDescriptor{Name: "Dropped"}, ReportReference: "dropped"},
+				{FieldDescriptor: FieldDescriptor{Name: "Climb"}, TBAReference: "climbRobot{{.RobotPosition}}"},
+			},
+			expected: Diagnostics{
+				UnresolvedReportReferences: []string{"Dropped"},
+				UnmatchedTBAReferences:     []string{"Climb"},
+			},
+		},
+		{
+			name: "undefined references",
+			schema: Schema{
+				{FieldDescriptor: FieldDescriptor{Name: "Total"}, Sum: []FieldDescriptor{{Name: "Made"}, {Name: "Missed"}}},
+				{FieldDescriptor: FieldDescriptor{Name: "Made"}, ReportReference: "made"},
+				{FieldDescriptor: FieldDescriptor{Name: "Climbed"}, AnyOf: []EqualExpression{{FieldDescriptor: FieldDescriptor{Name: "endgame"}, Equals: "HabLevel1"}}},
+				{FieldDescriptor: FieldDescriptor{Name: "Ratio"}, Expression: "Made / Attempts"},
+			},
+			expected: Diagnostics{
+				UndefinedReferences: []UndefinedReference{
+					{Field: "Total", Reference: "Made"},
+					{Field: "Total", Reference: "Missed"},
+					{Field: "Climbed", Reference: "endgame"},
+					{Field: "Ratio", Reference: "Attempts"},
+				},
+			},
+		},
+		{
+			name: "invalid fields",
+			schema: Schema{
+				{FieldDescriptor: FieldDescriptor{Name: "Made"}, ReportReference: "made"},
+				{FieldDescriptor: FieldDescriptor{Name: "Broken"}, Expression: "Made +"},
+				{FieldDescriptor: FieldDescriptor{Name: "Template"}, TBAReference: "endgame{{.Robot"},
+				{FieldDescriptor: FieldDescriptor{Name: "Empty"}},
+			},
+			expected: Diagnostics{
+				InvalidFields: []InvalidField{{Field: "Broken"}, {Field: "Template"}, {Field: "Empty"}},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := Diagnose(tt.schema, matches)
+
+			if !cmp.Equal(tt.expected, diagnostics, cmpopts.EquateEmpty(), cmpopts.IgnoreFields(InvalidField{}, "Err")) {
+				t.Errorf("expected diagnostics to equal expected, but got diff: %s", cmp.Diff(tt.expected, diagnostics, cmpopts.EquateEmpty(), cmpopts.IgnoreFields(InvalidField{}, "Err")))
+			}
+
+			for _, field := range diagnostics.InvalidFields {
+				if field.Err == nil {
+					t.Errorf("expected invalid field %q to have an error", field.Field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package summary
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a parsed arithmetic expression over the values of other schema fields in
+// a match. Expressions support numbers, field references, +, -, *, /, the comparisons <,
+// <=, >, >=, ==, and != (which are 1 if true and 0 if false), parentheses, and the
+// functions min(a, b, ...), max(a, b, ...), and if(condition, then, else), where any
+// non-zero condition is true. Field names that are not simple identifiers (e.g. that
+// contain spaces) are referenced in brackets, such as [Cargo Made].
+//
+// Dividing by zero results in zero. If any referenced field has no value in a match, the
+// expression has no value for that match either, the same as a Sum field.
+type Expression struct {
+	root node
+	refs []string
+}
+
+// ParseExpression parses an expression, returning an error if it is invalid.
+func ParseExpression(src string) (Expression, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return Expression{}, err
+	}
+
+	p := &parser{tokens: tokens, refs: make(map[string]bool)}
+	root, err := p.parseExpression()
+	if err != nil {
+		return Expression{}, err
+	}
+	if p.pos != len(p.tokens) {
+		return Expression{}, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+
+	return Expression{root: root, refs: p.order}, nil
+}
+
+// References returns the names of the fields the expression references, in the order they
+// first appear.
+func (e Expression) References() []string {
+	return e.refs
+}
+
+// Evaluate evaluates the expression using the given field values. It returns false if a
+// referenced field has no value.
+func (e Expression) Evaluate(values map[string]float64) (float64, bool) {
+	for _, ref := range e.refs {
+		if _, ok := values[ref]; !ok {
+			return 0, false
+		}
+	}
+
+	return e.root.eval(values), true
+}
+
+type node interface {
+	eval(values map[string]float64) float64
+}
+
+type numberNode float64
+
+func (n numberNode) eval(map[string]float64) float64 { return float64(n) }
+
+type refNode string
+
+func (n refNode) eval(values map[string]float64) float64 { return values[string(n)] }
+
+type negateNode struct{ operand node }
+
+func (n negateNode) eval(values map[string]float64) float64 { return -n.operand.eval(values) }
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (n binaryNode) eval(values map[string]float64) float64 {
+	l, r := n.left.eval(values), n.right.eval(values)
+
+	switch n.op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		if r == 0 {
+			return 0
+		}
+		return l / r
+	case "<":
+		return boolValue(l < r)
+	case "<=":
+		return boolValue(l <= r)
+	case ">":
+		return boolValue(l > r)
+	case ">=":
+		return boolValue(l >= r)
+	case "==":
+		return boolValue(l == r)
+	default:
+		return boolValue(l != r)
+	}
+}
+
+type callNode struct {
+	name string
+	args []node
+}
+
+func (n callNode) eval(values map[string]float64) float64 {
+	switch n.name {
+	case "if":
+		if n.args[0].eval(values) != 0 {
+			return n.args[1].eval(values)
+		}
+		return n.args[2].eval(values)
+	case "min":
+		v := n.args[0].eval(values)
+		for _, arg := range n.args[1:] {
+			v = math.Min(v, arg.eval(values))
+		}
+		return v
+	default:
+		v := n.args[0].eval(values)
+		for _, arg := range n.args[1:] {
+			v = math.Max(v, arg.eval(values))
+		}
+		return v
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenIdent
+	tokenRef
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i])})
+		case r == '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unclosed [ at position %d", i)
+			}
+
+			name := strings.TrimSpace(string(runes[i+1 : end]))
+			if name == "" {
+				return nil, fmt.Errorf("empty field reference at position %d", i)
+			}
+
+			tokens = append(tokens, token{kind: tokenRef, text: name})
+			i = end + 1
+		default:
+			op := string(r)
+			if i+1 < len(runes) && strings.Contains("<>=!", op) && runes[i+1] == '=' {
+				op += "="
+			}
+
+			switch op {
+			case "+", "-", "*", "/", "(", ")", ",", "<", "<=", ">", ">=", "==", "!=":
+			default:
+				return nil, fmt.Errorf("unexpected %q at position %d", op, i)
+			}
+
+			tokens = append(tokens, token{kind: tokenOp, text: op})
+			i += len([]rune(op))
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	refs   map[string]bool
+	order  []string
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// acceptOp consumes the next token if it is one of the given operators.
+func (p *parser) acceptOp(ops ...string) (string, bool) {
+	t, ok := p.peek()
+	if !ok || t.kind != tokenOp {
+		return "", false
+	}
+
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *parser) parseExpression() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	if op, ok := p.acceptOp("<", "<=", ">", ">=", "==", "!="); ok {
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: op, left: left, right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.acceptOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.acceptOp("*", "/")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.acceptOp("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) addRef(name string) node {
+	if !p.refs[name] {
+		p.refs[name] = true
+		p.order = append(p.order, name)
+	}
+	return refNode(name)
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+
+	switch t.kind {
+	case tokenNumber:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.text)
+		}
+		return numberNode(v), nil
+	case tokenRef:
+		return p.addRef(t.text), nil
+	case tokenIdent:
+		if _, ok := p.acceptOp("("); ok {
+			return p.parseCall(t.text)
+		}
+		return p.addRef(t.text), nil
+	}
+
+	if t.text == "(" {
+		inner, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.acceptOp(")"); !ok {
+			return nil, fmt.Errorf("expected )")
+		}
+		return inner, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+// parseCall parses the arguments of a function call after the opening parenthesis.
+func (p *parser) parseCall(name string) (node, error) {
+	if name != "min" && name != "max" && name != "if" {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+
+	var args []node
+	if _, ok := p.acceptOp(")"); !ok {
+		for {
+			arg, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if _, ok := p.acceptOp(","); ok {
+				continue
+			}
+			if _, ok := p.acceptOp(")"); ok {
+				break
+			}
+			return nil, fmt.Errorf("expected , or ) in call to %s", name)
+		}
+	}
+
+	if name == "if" && len(args) != 3 {
+		return nil, fmt.Errorf("if takes 3 arguments but got %d", len(args))
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s takes at least 1 argument", name)
+	}
+
+	return callNode{name: name, args: args}, nil
+}
@@ -0,0 +1,186 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExpression(t *testing.T) {
+	values := map[string]float64{"made": 3, "missed": 1, "Cargo Ship": 2, "zero": 0}
+
+	testCases := []struct {
+		name         string
+		expression   string
+		expectErr    bool
+		expectedRefs []string
+		expected     float64
+		expectMiss   bool
+	}{
+		{name: "number", expression: "4.5", expected: 4.5},
+		{name: "precedence", expression: "1 + 2 * 3 - 4 / 2", expected: 5},
+		{name: "parentheses", expression: "(1 + 2) * 3", expected: 9},
+		{name: "unary minus", expression: "-made + --2", expectedRefs: []string{"made"}, expected: -1},
+		{name: "ratio", expression: "made / (made + missed)", expectedRefs: []string{"made", "missed"}, expected: 0.75},
+		{name: "bracketed reference", expression: "[Cargo Ship] * 2 + [ made ]", expectedRefs: []string{"Cargo Ship", "made"}, expected: 7},
+		{name: "divide by zero", expression: "made / zero", expectedRefs: []string{"made", "zero"}, expected: 0},
+		{name: "min and max", expression: "min(made, 5, missed) + max(made, [Cargo Ship])", expectedRefs: []string{"made", "missed", "Cargo Ship"}, expected: 4},
+		{name: "if true", expression: "if(made > missed, 10, 20)", expectedRefs: []string{"made", "missed"}, expected: 10},
+		{name: "if false", expression: "if(made == missed, 10, 20)", expectedRefs: []string{"made", "missed"}, expected: 20},
+		{name: "comparisons", expression: "(1 < 2) + (2 <= 2) + (1 >= 2) + (1 != 1)", expected: 2},
+		{name: "missing reference", expression: "made + dropped", expectedRefs: []string{"made", "dropped"}, expectMiss: true},
+		{name: "empty", expression: "", expectErr: true},
+		{name: "unknown function", expression: "sqrt(4)", expectErr: true},
+		{name: "wrong if arguments", expression: "if(1, 2)", expectErr: true},
+		{name: "no min arguments", expression: "min()", expectErr: true},
+		{name: "unclosed parenthesis", expression: "(1 + 2", expectErr: true},
+		{name: "unclosed bracket", expression: "[Cargo Ship", expectErr: true},
+		{name: "trailing tokens", expression: "1 2", expectErr: true},
+		{name: "invalid operator", expression: "1 % 2", expectErr: true},
+		{name: "invalid number", expression: "1.2.3", expectErr: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseExpression(tt.expression)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error but got: %v", err)
+			}
+
+			if !cmp.Equal(tt.expectedRefs, expr.References()) {
+				t.Errorf("expected references to equal expected, but got diff: %s", cmp.Diff(tt.expectedRefs, expr.References()))
+			}
+
+			value, ok := expr.Evaluate(values)
+			if ok == tt.expectMiss {
+				t.Errorf("expected ok to be %t, but got %t", !tt.expectMiss, ok)
+			}
+			if value != tt.expected {
+				t.Errorf("expected value %v, but got %v", tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	testCases := []struct {
+		name      string
+		schema    Schema
+		expectErr bool
+	}{
+		{
+			name: "valid",
+			schema: Schema{
+				{FieldDescriptor: FieldDescriptor{Name: "Made"}, ReportReference: "made"},
+				{FieldDescriptor: FieldDescriptor{Name: "Missed"}, ReportReference: "missed"},
+				{FieldDescriptor: FieldDescriptor{Name: "Accuracy"}, Expression: "Made / (Made + Missed)"},
+			},
+		},
+		{
+			name: "invalid expression",
+			schema: Schema{
+				{FieldDescriptor: FieldDescriptor{Name: "Made"}, ReportReference: "made"},
+				{FieldDescriptor: FieldDescriptor{Name: "Accuracy"}, Expression: "Made /"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "reference to later field",
+			schema: Schema{
+				{FieldDescriptor: FieldDescriptor{Name: "Accuracy"}, Expression: "Made / 2"},
+				{FieldDescriptor: FieldDescriptor{Name: "Made"}, ReportReference: "made"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "expression with report reference",
+			schema: Schema{
+				{FieldDescriptor: FieldDescriptor{Name: "Made"}, ReportReference: "made"},
+				{FieldDescriptor: FieldDescriptor{Name: "Double"}, ReportReference: "made", Expression: "Made * 2"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "expression with sum",
+			schema: Schema{
+				{FieldDescriptor: FieldDescriptor{Name: "Made"}, ReportReference: "made"},
+				{FieldDescriptor: FieldDescriptor{Name: "Double"}, Sum: []FieldDescriptor{{Name: "Made"}}, Expression: "Made * 2"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "self reference",
+			schema: Schema{
+				{FieldDescriptor: FieldDescriptor{Name: "Accuracy"}, Expression: "Accuracy + 1"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchema(tt.schema)
+			if tt.expectErr != (err != nil) {
+				t.Errorf("expected error to be %t, but got: %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestSummarizeExpression(t *testing.T) {
+	schema := Schema{
+		{FieldDescriptor: FieldDescriptor{Name: "Made"}, ReportReference: "made"},
+		{FieldDescriptor: FieldDescriptor{Name: "Missed"}, ReportReference: "missed"},
+		{FieldDescriptor: FieldDescriptor{Name: "Accuracy"}, Expression: "Made / (Made + Missed)"},
+	}
+
+	report := func(made, missed float64) Report {
+		return Report{{Name: "made", Value: made}, {Name: "missed", Value: missed}}
+	}
+
+	matches := []Match{
+		{Key: "qm1", Reports: []Report{report(3, 1), report(1, 3)}},
+		{Key: "qm2", Reports: []Report{report(0, 0)}},
+		{Key: "qm3"},
+	}
+
+	values, err := SummarizeMatch(schema, matches[0])
+	if err != nil {
+		t.Fatalf("did not expect error but got: %v", err)
+	}
+	if values["Accuracy"] != 0.5 {
+		t.Errorf("expected accuracy from averaged reports to be 0.5, but got %v", values["Accuracy"])
+	}
+
+	summary, err := SummarizeTeam(schema, matches)
+	if err != nil {
+		t.Fatalf("did not expect error but got: %v", err)
+	}
+
+	for _, stat := range summary {
+		if stat.Name == "Accuracy" && (stat.Count != 2 || stat.Average != 0.25) {
+			t.Errorf("expected accuracy over 2 matches with average 0.25, but got %d matches with average %v", stat.Count, stat.Average)
+		}
+	}
+
+	parsed := append(Schema{}, schema...)
+	parsed.ParseExpressions()
+	if parsed[2].expr == nil {
+		t.Fatalf("expected expression to be parsed")
+	}
+
+	parsedSummary, err := SummarizeTeam(parsed, matches)
+	if err != nil {
+		t.Fatalf("did not expect error but got: %v", err)
+	}
+
+	if !cmp.Equal(summary, parsedSummary) {
+		t.Errorf("expected summary from parsed schema to equal summary, but got diff: %s", cmp.Diff(summary, parsedSummary))
+	}
+}
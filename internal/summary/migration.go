@@ -0,0 +1,36 @@
+package summary
+
+// Migration describes how to update a report from the previous version of a schema to
+// Version. Rename maps old report field names to new ones, and Scale multiplies the value
+// of a field (by its new name) after it is renamed.
+type Migration struct {
+	Version int64
+	Rename  map[string]string
+	Scale   map[string]float64
+}
+
+// MigrateReport updates a report stored under a version of a schema to the latest version,
+// applying every migration newer than version in order. Migrations must be sorted by
+// version. The report is not modified.
+func MigrateReport(report Report, version int64, migrations []Migration) Report {
+	migrated := make(Report, len(report))
+	copy(migrated, report)
+
+	for _, migration := range migrations {
+		if migration.Version <= version {
+			continue
+		}
+
+		for i, field := range migrated {
+			if name, ok := migration.Rename[field.Name]; ok {
+				field.Name = name
+			}
+			if scale, ok := migration.Scale[field.Name]; ok {
+				field.Value *= scale
+			}
+			migrated[i] = field
+		}
+	}
+
+	return migrated
+}
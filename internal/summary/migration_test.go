@@ -0,0 +1,57 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMigrateReport(t *testing.T) {
+	migrations := []Migration{
+		{Version: 2, Rename: map[string]string{"hatches": "Hatch Panels"}},
+		{Version: 3, Rename: map[string]string{"Hatch Panels": "Hatches", "climb": "Climb Seconds"}, Scale: map[string]float64{"Climb Seconds": 60}},
+	}
+
+	report := Report{{Name: "hatches", Value: 2}, {Name: "climb", Value: 0.5}, {Name: "cargo", Value: 3}}
+
+	testCases := []struct {
+		name     string
+		version  int64
+		expected Report
+	}{
+		{
+			name:     "first version",
+			version:  1,
+			expected: Report{{Name: "Hatches", Value: 2}, {Name: "Climb Seconds", Value: 30}, {Name: "cargo", Value: 3}},
+		},
+		{
+			name:     "unversioned",
+			version:  0,
+			expected: Report{{Name: "Hatches", Value: 2}, {Name: "Climb Seconds", Value: 30}, {Name: "cargo", Value: 3}},
+		},
+		{
+			name:     "middle version",
+			version:  2,
+			expected: Report{{Name: "hatches", Value: 2}, {Name: "Climb Seconds", Value: 30}, {Name: "cargo", Value: 3}},
+		},
+		{
+			name:     "latest version",
+			version:  3,
+			expected: report,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			migrated := MigrateReport(report, tt.version, migrations)
+
+			if !cmp.Equal(tt.expected, migrated) {
+				t.Errorf("expected migrated report to equal expected, but got diff: %s", cmp.Diff(tt.expected, migrated))
+			}
+		})
+	}
+
+	if report[0].Name != "hatches" {
+		t.Errorf("expected report to not be modified, but got %v", report)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"math"
+	"sort"
 )
 
 // Report defines a report for a single team in a single match at a single event, which is
@@ -43,13 +45,62 @@ type FieldDescriptor struct {
 }
 
 // SchemaField is a singular schema field. Only specify one of: ReportReference, TBAReference,
-// Sum, or AnyOf.
+// Sum, AnyOf, or Expression.
 type SchemaField struct {
 	FieldDescriptor
 	ReportReference string
 	TBAReference    string
 	Sum             []FieldDescriptor
 	AnyOf           []EqualExpression
+	Expression      string
+
+	// expr is Expression parsed by ParseExpressions.
+	expr *Expression
+}
+
+// ParseExpressions parses the Expression of every field in a schema, so that summarizing
+// with the schema doesn't parse them again for every match. Fields with invalid
+// expressions are left unparsed, and fail to summarize.
+func (s Schema) ParseExpressions() {
+	for i := range s {
+		if s[i].Expression == "" {
+			continue
+		}
+
+		if expr, err := ParseExpression(s[i].Expression); err == nil {
+			s[i].expr = &expr
+		}
+	}
+}
+
+// ValidateSchema checks that every Expression field in a schema doesn't also set another
+// way to summarize it, can be parsed, and only references fields that come before it,
+// since fields are summarized in order.
+func ValidateSchema(schema Schema) error {
+	names := make(map[string]bool)
+
+	for _, field := range schema {
+		if field.Expression != "" {
+			if field.ReportReference != "" || field.TBAReference != "" || len(field.Sum) != 0 || len(field.AnyOf) != 0 {
+				return fmt.Errorf("field %q has an expression, so it can't also have a report reference, TBA reference, sum, or any of", field.Name)
+			}
+
+			expr, err := ParseExpression(field.Expression)
+			if err != nil {
+				return fmt.Errorf("invalid expression for field %q: %w", field.Name, err)
+			}
+
+			for _, ref := range expr.References() {
+				if !names[ref] {
+					return fmt.Errorf("expression for field %q references %q, which is not an earlier field", field.Name, ref)
+				}
+			}
+		}
+
+		names[field.Name] = true
+	}
+
+	return nil
 }
 
 // EqualExpression defines a reference that should equal some JSON value (float64, number,
@@ -62,45 +113,60 @@ type EqualExpression struct {
 // Summary defines a summarized list of matches.
 type Summary []SummaryStat
 
-// SummaryStat defines a single stat in a match.
+// SummaryStat defines a single stat in a match. Count is the number of matches the stat
+// was summarized from, StdDev is their sample standard deviation, and Trend is the slope
+// of the least squares line through them in match order (the change per match).
 type SummaryStat struct {
 	FieldDescriptor
 	Max     float64
 	Average float64
+	Min     float64
+	Median  float64
+	StdDev  float64
+	Trend   float64
+	Count   int
 }
 
 // SummarizeTeam summarizes a singular team's performance in a single match. The matches
 // passed must be ONLY for the team being analyzed and have RobotPosition and ScoreBreakdown
-// set properly.
+// set properly, and should be in the order they were played.
 func SummarizeTeam(schema Schema, matches []Match) (Summary, error) {
+	return SummarizeTeamWindow(schema, matches, 0)
+}
+
+// SummarizeTeamWindow is like SummarizeTeam, but only summarizes each stat from the last
+// window matches that have a value for it, to show how a team is performing recently. A
+// window of zero or less summarizes every match.
+func SummarizeTeamWindow(schema Schema, matches []Match, window int) (Summary, error) {
 	records := make(map[string][]float64)
 
 	for _, match := range matches {
-		matchRecords, err := summarizeMatch(schema, match)
+		matchValues, err := SummarizeMatch(schema, match)
 		if err != nil {
-			return Summary{}, fmt.Errorf("unable to summarize match: %w", err)
+			return Summary{}, err
 		}
 
-		for statName, matchRecord := range matchRecords {
-			// if there are multiple reports for one match we need to
-			// average them so one match isn't weighted twice as much
-			// as another if it has two reports
-
-			var sum float64
-			for _, reportGroup := range matchRecord {
-				sum += sumJSONValues(reportGroup)
-			}
-
-			records[statName] = append(records[statName], sum/float64(len(matchRecord)))
+		for statName, value := range matchValues {
+			records[statName] = append(records[statName], value)
 		}
 	}
 
 	summary := make(Summary, 0)
 	for statName, record := range records {
+		if window > 0 && len(record) > window {
+			record = record[len(record)-window:]
+		}
+
+		average := sum(record) / float64(len(record))
 		stat := SummaryStat{
 			FieldDescriptor: FieldDescriptor{Name: statName},
 			Max:             max(record),
-			Average:         sum(record) / float64(len(record)),
+			Average:         average,
+			Min:             min(record),
+			Median:          median(record),
+			StdDev:          stdDev(record, average),
+			Trend:           trend(record, average),
+			Count:           len(record),
 		}
 
 		summary = append(summary, stat)
@@ -109,9 +175,35 @@ func SummarizeTeam(schema Schema, matches []Match) (Summary, error) {
 	return summary, nil
 }
 
+// SummarizeMatch finds the value of every stat for a team in a single match, which is what
+// SummarizeTeam summarizes across matches. Stats the match has no value for (e.g. report
+// stats for a match without reports) are left out.
+func SummarizeMatch(schema Schema, match Match) (map[string]float64, error) {
+	matchRecords, err := summarizeMatch(schema, match)
+	if err != nil {
+		return nil, fmt.Errorf("unable to summarize match: %w", err)
+	}
+
+	values := make(map[string]float64)
+	for statName, matchRecord := range matchRecords {
+		// if there are multiple reports for one match we need to
+		// average them so one match isn't weighted twice as much
+		// as another if it has two reports
+
+		var sum float64
+		for _, reportGroup := range matchRecord {
+			sum += sumJSONValues(reportGroup)
+		}
+
+		values[statName] = sum / float64(len(matchRecord))
+	}
+
+	return values, nil
+}
+
 func max(values []float64) float64 {
-	var max float64
-	for _, v := range values {
+	max := values[0]
+	for _, v := range values[1:] {
 		if v > max {
 			max = v
 		}
@@ -119,6 +211,59 @@ func max(values []float64) float64 {
 	return max
 }
 
+func min(values []float64) float64 {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// stdDev returns the sample standard deviation of values, or 0 if there are less than two.
+func stdDev(values []float64, mean float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(squares / float64(len(values)-1))
+}
+
+// trend returns the slope of the least squares line through values, using each value's
+// index as x, or 0 if there are less than two.
+func trend(values []float64, mean float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	meanX := float64(len(values)-1) / 2
+
+	var covariance, varianceX float64
+	for i, v := range values {
+		x := float64(i) - meanX
+		covariance += x * (v - mean)
+		varianceX += x * x
+	}
+	return covariance / varianceX
+}
+
 func sum(values []float64) float64 {
 	var sum float64
 	for _, v := range values {
@@ -166,8 +311,12 @@ func summarizeMatch(schema Schema, match Match) (rawRecords, error) {
 			if err := summarizeAnyOf(statDescription, match, records); err != nil {
 				return nil, fmt.Errorf("unable to summarize any of stat: %w", err)
 			}
+		} else if statDescription.Expression != "" {
+			if err := summarizeExpression(statDescription, match, records); err != nil {
+				return nil, fmt.Errorf("unable to summarize expression stat: %w", err)
+			}
 		} else {
-			return nil, errors.New("got invalid stat description: no ReportReference, TBAReference, Sum, or AnyOf")
+			return nil, errors.New("got invalid stat description: no ReportReference, TBAReference, Sum, AnyOf, or Expression")
 		}
 	}
 
@@ -192,18 +341,28 @@ type templateData struct {
 	RobotPosition int
 }
 
-func summarizeTBAReference(statDescription SchemaField, match Match, records rawRecords) error {
-	tmpl, err := template.New("key").Parse(statDescription.TBAReference)
+// tbaKey executes a TBA reference template to find the score breakdown key for a robot.
+func tbaKey(tbaReference string, robotPosition int) (string, error) {
+	tmpl, err := template.New("key").Parse(tbaReference)
 	if err != nil {
-		return fmt.Errorf("unable to parse tba reference template: %w", err)
+		return "", fmt.Errorf("unable to parse tba reference template: %w", err)
 	}
 
 	buf := new(bytes.Buffer)
-	if err := tmpl.Execute(buf, templateData{RobotPosition: match.RobotPosition}); err != nil {
-		return fmt.Errorf("unable to execute template: %w", err)
+	if err := tmpl.Execute(buf, templateData{RobotPosition: robotPosition}); err != nil {
+		return "", fmt.Errorf("unable to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func summarizeTBAReference(statDescription SchemaField, match Match, records rawRecords) error {
+	key, err := tbaKey(statDescription.TBAReference, match.RobotPosition)
+	if err != nil {
+		return err
 	}
 
-	value, ok := match.ScoreBreakdown[buf.String()]
+	value, ok := match.ScoreBreakdown[key]
 	if !ok {
 		return nil
 	}
@@ -261,6 +420,43 @@ func summarizeAnyOf(statDescription SchemaField, match Match, records rawRecords
 	return nil
 }
 
+func summarizeExpression(statDescription SchemaField, match Match, records rawRecords) error {
+	expr := statDescription.expr
+	if expr == nil {
+		parsed, err := ParseExpression(statDescription.Expression)
+		if err != nil {
+			return fmt.Errorf("unable to parse expression: %w", err)
+		}
+		expr = &parsed
+	}
+
+	values := make(map[string]float64)
+	for _, ref := range expr.References() {
+		refRecords := records[ref]
+		if len(refRecords) == 0 {
+			// like sums, expressions can't be resolved if one of the
+			// records is missing
+			return nil
+		}
+
+		var sum float64
+		for _, reportGroup := range refRecords {
+			sum += sumJSONValues(reportGroup)
+		}
+
+		values[ref] = sum / float64(len(refRecords))
+	}
+
+	value, ok := expr.Evaluate(values)
+	if !ok {
+		return nil
+	}
+
+	records[statDescription.Name] = append(records[statDescription.Name], []interface{}{value})
+
+	return nil
+}
+
 func compareRecords(a, b interface{}) bool {
 	aString, aOk := a.(string)
 	bString, bOk := b.(string)
@@ -1,10 +1,12 @@
 package summary
 
 import (
+	"math"
 	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestSummarizeTeam(t *testing.T) {
@@ -22,8 +24,134 @@ func TestSummarizeTeam(t *testing.T) {
 		return testSummary[i].Name < testSummary[j].Name
 	})
 
-	if !cmp.Equal(actualSummary, testSummary) {
-		t.Errorf("expected actual summary to equal test summary but got diff: %v\n", cmp.Diff(actualSummary, testSummary))
+	if !cmp.Equal(actualSummary, testSummary, cmpopts.EquateApprox(0, 1e-9)) {
+		t.Errorf("expected actual summary to equal test summary but got diff: %v\n", cmp.Diff(actualSummary, testSummary, cmpopts.EquateApprox(0, 1e-9)))
+	}
+}
+
+func TestSummarizeTeamWindow(t *testing.T) {
+	schema := Schema{
+		{FieldDescriptor: FieldDescriptor{Name: "Cargo"}, ReportReference: "cargo"},
+	}
+
+	match := func(key string, cargo ...float64) Match {
+		m := Match{Key: key}
+		for _, c := range cargo {
+			m.Reports = append(m.Reports, Report{{Name: "cargo", Value: c}})
+		}
+		return m
+	}
+
+	matches := []Match{
+		match("qm1", 2),
+		match("qm2", 4, 6),
+		match("qm3"),
+		match("qm4", 1),
+		match("qm5", 8),
+	}
+
+	testCases := []struct {
+		name     string
+		window   int
+		expected Summary
+	}{
+		{
+			name: "all matches",
+			expected: Summary{{
+				FieldDescriptor: FieldDescriptor{Name: "Cargo"},
+				Max:             8,
+				Average:         4,
+				Min:             1,
+				Median:          3.5,
+				StdDev:          math.Sqrt(10),
+				Trend:           1.4,
+				Count:           4,
+			}},
+		},
+		{
+			name:   "last two matches",
+			window: 2,
+			expected: Summary{{
+				FieldDescriptor: FieldDescriptor{Name: "Cargo"},
+				Max:             8,
+				Average:         4.5,
+				Min:             1,
+				Median:          4.5,
+				StdDev:          math.Sqrt(24.5),
+				Trend:           7,
+				Count:           2,
+			}},
+		},
+		{
+			name:   "window larger than matches",
+			window: 10,
+			expected: Summary{{
+				FieldDescriptor: FieldDescriptor{Name: "Cargo"},
+				Max:             8,
+				Average:         4,
+				Min:             1,
+				Median:          3.5,
+				StdDev:          math.Sqrt(10),
+				Trend:           1.4,
+				Count:           4,
+			}},
+		},
+		{
+			name:   "last match",
+			window: 1,
+			expected: Summary{{
+				FieldDescriptor: FieldDescriptor{Name: "Cargo"},
+				Max:             8,
+				Average:         8,
+				Min:             8,
+				Median:          8,
+				Count:           1,
+			}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := SummarizeTeamWindow(schema, matches, tt.window)
+			if err != nil {
+				t.Fatalf("did not expect error but got: %v", err)
+			}
+
+			if !cmp.Equal(tt.expected, actual, cmpopts.EquateApprox(0, 1e-9)) {
+				t.Errorf("expected summary to equal expected but got diff: %v", cmp.Diff(tt.expected, actual, cmpopts.EquateApprox(0, 1e-9)))
+			}
+		})
+	}
+}
+
+func TestSummarizeTeamNegativeValues(t *testing.T) {
+	schema := Schema{
+		{FieldDescriptor: FieldDescriptor{Name: "Penalties"}, ReportReference: "penalties"},
+	}
+
+	matches := []Match{
+		{Key: "qm1", Reports: []Report{{{Name: "penalties", Value: -3}}}},
+		{Key: "qm2", Reports: []Report{{{Name: "penalties", Value: -1}}}},
+	}
+
+	expected := Summary{{
+		FieldDescriptor: FieldDescriptor{Name: "Penalties"},
+		Max:             -1,
+		Average:         -2,
+		Min:             -3,
+		Median:          -2,
+		StdDev:          math.Sqrt(2),
+		Trend:           2,
+		Count:           2,
+	}}
+
+	actual, err := SummarizeTeam(schema, matches)
+	if err != nil {
+		t.Fatalf("did not expect error but got: %v", err)
+	}
+
+	if !cmp.Equal(expected, actual, cmpopts.EquateApprox(0, 1e-9)) {
+		t.Errorf("expected summary to equal expected but got diff: %v", cmp.Diff(expected, actual, cmpopts.EquateApprox(0, 1e-9)))
 	}
 }
 
@@ -165,98 +293,168 @@ var testSummary Summary = []SummaryStat{
 		FieldDescriptor: FieldDescriptor{Name: "Cargo Placed"},
 		Average:         0,
 		Max:             0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Hatches Placed"},
 		Average:         12.0 / 9.0,
 		Max:             2,
+		Median:          1,
+		StdDev:          math.Sqrt(1.0 / 2.0),
+		Trend:           1.0 / 20.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Cargo Ship Hatches"},
 		Average:         2.0 / 9.0,
 		Max:             1,
+		StdDev:          math.Sqrt(7.0 / 36.0),
+		Trend:           1.0 / 20.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Cargo Ship Cargo"},
 		Average:         8.0 / 9.0,
 		Max:             4,
+		StdDev:          math.Sqrt(85.0 / 36.0),
+		Trend:           -13.0 / 60.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Rocket Hatches Lvl 1"},
 		Average:         7.0 / 9.0,
 		Max:             2,
+		Median:          1,
+		StdDev:          2.0 / 3.0,
+		Trend:           -1.0 / 60.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Rocket Cargo Lvl 1"},
 		Average:         5.0 / 3.0,
 		Max:             2,
+		Median:          2,
+		StdDev:          math.Sqrt(1.0 / 2.0),
+		Trend:           -1.0 / 60.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Rocket Hatches Lvl 2"},
 		Average:         16.0 / 9.0,
 		Max:             2,
+		Median:          2,
+		StdDev:          2.0 / 3.0,
+		Trend:           1.0 / 10.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Rocket Cargo Lvl 2"},
 		Average:         16.0 / 9.0,
 		Max:             2,
+		Median:          2,
+		StdDev:          2.0 / 3.0,
+		Trend:           1.0 / 10.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Rocket Hatches Lvl 3"},
 		Average:         4.0 / 3.0,
 		Max:             2,
+		Median:          2,
+		StdDev:          1,
+		Trend:           2.0 / 15.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Rocket Cargo Lvl 3"},
 		Average:         1.0,
 		Max:             2,
+		Median:          1,
+		StdDev:          1,
+		Trend:           3.0 / 20.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Climbed Lvl 1"},
 		Average:         0.4375,
 		Max:             1,
+		StdDev:          math.Sqrt(21.0 / 80.0),
+		Trend:           1.0 / 680.0,
+		Count:           16,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Climbed Lvl 1+"},
 		Average:         0.9375,
 		Max:             1,
+		Median:          1,
+		StdDev:          1.0 / 4.0,
+		Trend:           1.0 / 680.0,
+		Count:           16,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Climbed Lvl 2"},
 		Average:         0.0625,
 		Max:             1,
+		StdDev:          1.0 / 4.0,
+		Trend:           -3.0 / 680.0,
+		Count:           16,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Climbed Lvl 2+"},
 		Average:         0.5,
 		Max:             1,
+		Median:          1.0 / 2.0,
+		StdDev:          math.Sqrt(4.0 / 15.0),
+		Count:           16,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Climbed Lvl 3"},
 		Average:         0.4375,
 		Max:             1,
+		StdDev:          math.Sqrt(21.0 / 80.0),
+		Trend:           3.0 / 680.0,
+		Count:           16,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Assisted Climb Points"},
 		Average:         0,
 		Max:             0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Teleop Hatches"},
 		Average:         37.0 / 9.0,
 		Max:             6,
+		Min:             1,
+		Median:          5,
+		StdDev:          math.Sqrt(47.0 / 18.0),
+		Trend:           4.0 / 15.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Teleop Cargo"},
 		Average:         48.0 / 9.0,
 		Max:             7,
+		Min:             3,
+		Median:          6,
+		StdDev:          math.Sqrt(2),
+		Trend:           1.0 / 60.0,
+		Count:           9,
 	},
 	{
 		FieldDescriptor: FieldDescriptor{Name: "Teleop Gamepieces"},
 		Average:         85.0 / 9.0,
 		Max:             12,
+		Min:             7,
+		Median:          9,
+		StdDev:          math.Sqrt(41.0 / 18.0),
+		Trend:           17.0 / 60.0,
+		Count:           9,
+	},
+	{
+		FieldDescriptor: FieldDescriptor{Name: "endgame"},
+		Count:           16,
 	},
-	{FieldDescriptor: FieldDescriptor{Name: "endgame"}},
 }
 
 var testMatches = []Match{
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,9 +18,10 @@ import (
 // Service provides methods for retrieving data from
 // The Blue Alliance API
 type Service struct {
-	URL       string
-	APIKey    string
-	etagStore *sync.Map
+	URL         string
+	APIKey      string
+	etagStore   *sync.Map
+	expiryStore sync.Map
 }
 
 type district struct {
@@ -91,6 +93,11 @@ type sortOrderInfo struct {
 	Name string `json:"name"`
 }
 
+type eliminationAlliance struct {
+	Declines []string `json:"declines"`
+	Picks    []string `json:"picks"`
+}
+
 // Maximum size of response from the TBA API to read. This value is about 4x the
 // size of a typical /events/{year} response from TBA.
 const maxResponseSize int64 = 1.2e+6
@@ -142,6 +149,10 @@ func (s *Service) makeRequest(ctx context.Context, path string) (*http.Response,
 		return resp, err
 	}
 
+	if maxAge, ok := cacheMaxAge(resp.Header.Get("Cache-Control")); ok {
+		s.expiryStore.Store(path, time.Now().Add(maxAge))
+	}
+
 	if resp.StatusCode == http.StatusNotModified {
 		return resp, ErrNotModified{fmt.Errorf("got not modified for path: %s", path)}
 	}
@@ -153,6 +164,36 @@ func (s *Service) makeRequest(ctx context.Context, path string) (*http.Response,
 	return resp, nil
 }
 
+// cacheMaxAge returns the max-age directive of a Cache-Control header, if it has one.
+func cacheMaxAge(header string) (time.Duration, bool) {
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+
+		seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil || seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	return 0, false
+}
+
+// MatchesExpiry returns when the last response for an event's matches stops being fresh
+// according to its Cache-Control max-age, or the zero time if it's unknown. Requests before
+// then are likely to get the same response.
+func (s *Service) MatchesExpiry(eventKey string) time.Time {
+	if v, ok := s.expiryStore.Load(fmt.Sprintf("/event/%s/matches", eventKey)); ok {
+		return v.(time.Time)
+	}
+
+	return time.Time{}
+}
+
 func webcastURL(webcastType, channel string) (string, error) {
 	switch webcastType {
 	case "twitch":
@@ -253,6 +294,7 @@ func (s *Service) GetEvents(ctx context.Context, year int) ([]store.Event, error
 			Week:         tbaEvent.Week,
 			StartDate:    startDate,
 			EndDate:      endDate,
+			Timezone:     timeZone.String(),
 			Webcasts:     webcasts,
 			Lat:          tbaEvent.Lat,
 			Lon:          tbaEvent.Lng,
@@ -286,68 +328,76 @@ func (s *Service) GetMatches(ctx context.Context, eventKey string) ([]store.Matc
 
 	var matches []store.Match
 	for _, tbaMatch := range tbaMatches {
-		matchKey, err := trimMatchKey(tbaMatch.Key)
+		match, err := storeMatch(eventKey, tbaMatch)
 		if err != nil {
 			return nil, err
 		}
 
-		var predictedTime *time.Time
-		var actualTime *time.Time
-		var scheduledTime *time.Time
+		matches = append(matches, match)
+	}
 
-		if tbaMatch.PredictedTime != 0 {
-			timestamp := time.Unix(tbaMatch.PredictedTime, 0)
-			predictedTime = &timestamp
-		}
+	return matches, nil
+}
 
-		if tbaMatch.ActualTime != 0 {
-			timestamp := time.Unix(tbaMatch.ActualTime, 0)
-			actualTime = &timestamp
-		}
+// storeMatch converts a match from TBA at the given event to a store.Match.
+func storeMatch(eventKey string, tbaMatch match) (store.Match, error) {
+	matchKey, err := trimMatchKey(tbaMatch.Key)
+	if err != nil {
+		return store.Match{}, err
+	}
 
-		if tbaMatch.ScheduledTime != 0 {
-			timestamp := time.Unix(tbaMatch.ScheduledTime, 0)
-			scheduledTime = &timestamp
-		}
+	var predictedTime *time.Time
+	var actualTime *time.Time
+	var scheduledTime *time.Time
 
-		var redScore, blueScore *int
-		if tbaMatch.Alliances.Red.Score != nil && *tbaMatch.Alliances.Red.Score != -1 {
-			redScore = tbaMatch.Alliances.Red.Score
-		}
-		if tbaMatch.Alliances.Blue.Score != nil && *tbaMatch.Alliances.Blue.Score != -1 {
-			blueScore = tbaMatch.Alliances.Blue.Score
-		}
+	if tbaMatch.PredictedTime != 0 {
+		timestamp := time.Unix(tbaMatch.PredictedTime, 0)
+		predictedTime = &timestamp
+	}
 
-		videos := make([]string, 0)
-		for _, vid := range tbaMatch.Videos {
-			url, err := videoURL(vid.Type, vid.Key)
-			if err == nil {
-				videos = append(videos, url)
-			}
-		}
+	if tbaMatch.ActualTime != 0 {
+		timestamp := time.Unix(tbaMatch.ActualTime, 0)
+		actualTime = &timestamp
+	}
 
-		matchURL := fmt.Sprintf(tbaURL+"/match/%s", tbaMatch.Key)
-
-		match := store.Match{
-			Key:                matchKey,
-			EventKey:           eventKey,
-			PredictedTime:      predictedTime,
-			ActualTime:         actualTime,
-			ScheduledTime:      scheduledTime,
-			RedScore:           redScore,
-			BlueScore:          blueScore,
-			RedAlliance:        tbaMatch.Alliances.Red.TeamKeys,
-			BlueAlliance:       tbaMatch.Alliances.Blue.TeamKeys,
-			RedScoreBreakdown:  tbaMatch.ScoreBreakdown.Red,
-			BlueScoreBreakdown: tbaMatch.ScoreBreakdown.Blue,
-			TBAURL:             &matchURL,
-			Videos:             videos,
-		}
+	if tbaMatch.ScheduledTime != 0 {
+		timestamp := time.Unix(tbaMatch.ScheduledTime, 0)
+		scheduledTime = &timestamp
+	}
 
-		matches = append(matches, match)
+	var redScore, blueScore *int
+	if tbaMatch.Alliances.Red.Score != nil && *tbaMatch.Alliances.Red.Score != -1 {
+		redScore = tbaMatch.Alliances.Red.Score
+	}
+	if tbaMatch.Alliances.Blue.Score != nil && *tbaMatch.Alliances.Blue.Score != -1 {
+		blueScore = tbaMatch.Alliances.Blue.Score
 	}
 
-	return matches, nil
+	videos := make([]string, 0)
+	for _, vid := range tbaMatch.Videos {
+		url, err := videoURL(vid.Type, vid.Key)
+		if err == nil {
+			videos = append(videos, url)
+		}
+	}
+
+	matchURL := fmt.Sprintf(tbaURL+"/match/%s", tbaMatch.Key)
+
+	return store.Match{
+		Key:                matchKey,
+		EventKey:           eventKey,
+		PredictedTime:      predictedTime,
+		ActualTime:         actualTime,
+		ScheduledTime:      scheduledTime,
+		RedScore:           redScore,
+		BlueScore:          blueScore,
+		RedAlliance:        tbaMatch.Alliances.Red.TeamKeys,
+		BlueAlliance:       tbaMatch.Alliances.Blue.TeamKeys,
+		RedScoreBreakdown:  tbaMatch.ScoreBreakdown.Red,
+		BlueScoreBreakdown: tbaMatch.ScoreBreakdown.Blue,
+		TBAURL:             &matchURL,
+		Videos:             videos,
+	}, nil
 }
 
 // GetTeams retrieves all teams
@@ -428,3 +478,42 @@ func (s *Service) GetTeamRankings(ctx context.Context, eventKey string) ([]store
 
 	return teams, nil
 }
+
+// GetAlliances retrieves the alliance selection results from a specific event. If
+// alliance selection has not happened yet, the returned selection has no alliances.
+func (s *Service) GetAlliances(ctx context.Context, eventKey string) (store.AllianceSelection, error) {
+	path := fmt.Sprintf("/event/%s/alliances", eventKey)
+
+	selection := store.AllianceSelection{
+		EventKey:  eventKey,
+		Alliances: store.SelectionAlliances{},
+		Declined:  []string{},
+		FromTBA:   true,
+	}
+
+	response, err := s.makeRequest(ctx, path)
+	if err != nil {
+		return selection, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return selection, fmt.Errorf("got unexpected status for url %q: %d", response.Request.URL, response.StatusCode)
+	}
+
+	var tbaAlliances []eliminationAlliance
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxResponseSize)).Decode(&tbaAlliances); err != nil {
+		return selection, err
+	}
+
+	for _, alliance := range tbaAlliances {
+		picks := alliance.Picks
+		if picks == nil {
+			picks = []string{}
+		}
+
+		selection.Alliances = append(selection.Alliances, picks)
+		selection.Declined = append(selection.Declined, alliance.Declines...)
+	}
+
+	return selection, nil
+}
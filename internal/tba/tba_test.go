@@ -20,6 +20,7 @@ type tbaServer struct {
 	getMatchesHandler      func(w http.ResponseWriter, r *http.Request)
 	getTeamRankingsHandler func(w http.ResponseWriter, r *http.Request)
 	getTeamsHandler        func(w http.ResponseWriter, r *http.Request)
+	getAlliancesHandler    func(w http.ResponseWriter, r *http.Request)
 }
 
 const testingYear = 2018
@@ -48,6 +49,7 @@ func newTBAServer() *tbaServer {
 	r.HandleFunc("/event/{eventKey}/matches", func(w http.ResponseWriter, r *http.Request) { ts.getMatchesHandler(w, r) })
 	r.HandleFunc("/event/{eventKey}/rankings", func(w http.ResponseWriter, r *http.Request) { ts.getTeamRankingsHandler(w, r) })
 	r.HandleFunc("/teams/{page}", func(w http.ResponseWriter, r *http.Request) { ts.getTeamsHandler(w, r) })
+	r.HandleFunc("/event/{eventKey}/alliances", func(w http.ResponseWriter, r *http.Request) { ts.getAlliancesHandler(w, r) })
 
 	ts.Server = httptest.NewServer(r)
 
@@ -121,6 +123,7 @@ func TestGetEvents(t *testing.T) {
 					FullDistrict: nil,
 					StartDate:    time.Date(2018, 4, 2, 7+12, 0, 0, 0, time.UTC),
 					EndDate:      time.Date(2018, 4, 4, 7+12+7, 0, 0, 0, time.UTC),
+					Timezone:     "America/Los_Angeles",
 					Lat:          41.9911025,
 					Lon:          -70.993044,
 					GMapsURL:     newString("https://www.google.com/maps?cid=7437893320196269298"),
@@ -208,6 +211,7 @@ func TestGetEvents(t *testing.T) {
 				Week:         newInt(5),
 				StartDate:    time.Date(2018, 5, 6, 12, 0, 0, 0, time.UTC),
 				EndDate:      time.Date(2018, 5, 7, 12+7, 0, 0, 0, time.UTC),
+				Timezone:     "UTC",
 				Lat:          42.0,
 				Lon:          0.0,
 				GMapsURL:     newString("https://www.google.com/maps?cid=7437893320196269298"),
@@ -221,6 +225,7 @@ func TestGetEvents(t *testing.T) {
 				Week:         newInt(2),
 				StartDate:    time.Date(2018, 11, 19, 8+12, 0, 0, 0, time.UTC),
 				EndDate:      time.Date(2018, 11, 23, 8+12+7, 0, 0, 0, time.UTC),
+				Timezone:     "America/Los_Angeles",
 				Lat:          45.52,
 				Lon:          -122.681944,
 				GMapsURL:     newString("https://www.google.com/maps?cid=7437893320196269298"),
@@ -867,3 +872,169 @@ func TestGetTeamRankings(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAlliances(t *testing.T) {
+	server := newTBAServer()
+	defer server.Close()
+
+	APIKey := "notARealKey"
+
+	s := Service{URL: server.URL, APIKey: APIKey}
+
+	const eventKey = "2018abca"
+
+	testCases := []struct {
+		name                string
+		getAlliancesHandler func(w http.ResponseWriter, r *http.Request)
+		selection           store.AllianceSelection
+		expectErr           bool
+	}{
+		{
+			name: "tba alliances route gives 500",
+			getAlliancesHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			selection: store.AllianceSelection{
+				EventKey:  eventKey,
+				Alliances: store.SelectionAlliances{},
+				Declined:  []string{},
+				FromTBA:   true,
+			},
+			expectErr: true,
+		},
+		{
+			name: "alliance selection has not happened",
+			getAlliancesHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				if _, err := w.Write([]byte(`null`)); err != nil {
+					t.Errorf("failed to write test data")
+				}
+			},
+			selection: store.AllianceSelection{
+				EventKey:  eventKey,
+				Alliances: store.SelectionAlliances{},
+				Declined:  []string{},
+				FromTBA:   true,
+			},
+			expectErr: false,
+		},
+		{
+			name: "tba gives alliances and declines",
+			getAlliancesHandler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-TBA-Auth-Key") != APIKey {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				vars := mux.Vars(r)
+				if key, ok := vars["eventKey"]; !ok || key != eventKey {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`
+				[
+					{
+						"name": "Alliance 1",
+						"declines": ["frc1983"],
+						"picks": ["frc2733", "frc254", "frc1678"]
+					},
+					{
+						"name": "Alliance 2",
+						"declines": null,
+						"picks": ["frc4488", "frc5468", "frc3674"]
+					}
+				]
+				`))
+
+				if err != nil {
+					t.Errorf("failed to write test data")
+				}
+			},
+			selection: store.AllianceSelection{
+				EventKey: eventKey,
+				Alliances: store.SelectionAlliances{
+					{"frc2733", "frc254", "frc1678"},
+					{"frc4488", "frc5468", "frc3674"},
+				},
+				Declined: []string{"frc1983"},
+				FromTBA:  true,
+			},
+			expectErr: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			server.getAlliancesHandler = tt.getAlliancesHandler
+
+			selection, err := s.GetAlliances(context.TODO(), eventKey)
+			if !tt.expectErr && err != nil {
+				t.Errorf("did not expect an error but got one: %v", err)
+			} else if tt.expectErr && err == nil {
+				t.Errorf("expected error but didnt get one: %v", err)
+			}
+
+			if !cmp.Equal(selection, tt.selection) {
+				t.Errorf("expected selection does not equal actual selection, got dif: %s", cmp.Diff(tt.selection, selection))
+			}
+		})
+	}
+}
+
+func TestCacheMaxAge(t *testing.T) {
+	testCases := []struct {
+		header   string
+		maxAge   time.Duration
+		expectOK bool
+	}{
+		{header: "public, max-age=61", maxAge: time.Second * 61, expectOK: true},
+		{header: "Max-Age=0", expectOK: true},
+		{header: "no-cache"},
+		{header: ""},
+		{header: "max-age=soon"},
+		{header: "max-age=-5"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.header, func(t *testing.T) {
+			maxAge, ok := cacheMaxAge(tt.header)
+			if ok != tt.expectOK || maxAge != tt.maxAge {
+				t.Errorf("expected max age %v (%t), but got %v (%t)", tt.maxAge, tt.expectOK, maxAge, ok)
+			}
+		})
+	}
+}
+
+func TestMatchesExpiry(t *testing.T) {
+	server := newTBAServer()
+	defer server.Close()
+
+	s := Service{URL: server.URL, APIKey: "alsoNotARealKey"}
+
+	const eventKey = "2018alhu"
+
+	if expiry := s.MatchesExpiry(eventKey); !expiry.IsZero() {
+		t.Errorf("expected no expiry before any request, but got %v", expiry)
+	}
+
+	server.getMatchesHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=61")
+		w.Write([]byte("[]"))
+	}
+
+	before := time.Now()
+	if _, err := s.GetMatches(context.TODO(), eventKey); err != nil {
+		t.Fatalf("did not expect an error but got one: %v", err)
+	}
+
+	expiry := s.MatchesExpiry(eventKey)
+	if expiry.Before(before.Add(time.Second*61)) || expiry.After(time.Now().Add(time.Second*61)) {
+		t.Errorf("expected expiry 61 seconds after the request, but got %v", expiry)
+	}
+
+	if expiry := s.MatchesExpiry("2018other"); !expiry.IsZero() {
+		t.Errorf("expected no expiry for another event, but got %v", expiry)
+	}
+}
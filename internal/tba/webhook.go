@@ -0,0 +1,102 @@
+package tba
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+)
+
+// Webhook message types.
+const (
+	WebhookMatchScore        = "match_score"
+	WebhookUpcomingMatch     = "upcoming_match"
+	WebhookScheduleUpdated   = "schedule_updated"
+	WebhookAllianceSelection = "alliance_selection"
+	WebhookVerification      = "verification"
+	WebhookPing              = "ping"
+)
+
+// WebhookMessage is a message TBA sent to a webhook. Data depends on the message type.
+type WebhookMessage struct {
+	Type string          `json:"message_type"`
+	Data json.RawMessage `json:"message_data"`
+}
+
+// VerifyWebhook returns whether signature (the X-TBA-HMAC header) is the hex HMAC-SHA256 of
+// a webhook request body with the webhook secret.
+func VerifyWebhook(secret string, body []byte, signature string) bool {
+	given, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hmac.Equal(given, mac.Sum(nil))
+}
+
+// ParseWebhook parses a webhook request body.
+func ParseWebhook(body []byte) (WebhookMessage, error) {
+	var m WebhookMessage
+	if err := json.Unmarshal(body, &m); err != nil {
+		return m, fmt.Errorf("unable to decode webhook message: %w", err)
+	}
+
+	if m.Type == "" {
+		return m, errors.New("webhook message has no type")
+	}
+
+	return m, nil
+}
+
+// EventKey returns the key of the event a message is about.
+func (m WebhookMessage) EventKey() (string, error) {
+	var data struct {
+		EventKey string `json:"event_key"`
+	}
+	if err := json.Unmarshal(m.Data, &data); err != nil {
+		return "", fmt.Errorf("unable to decode %s message data: %w", m.Type, err)
+	}
+
+	if data.EventKey == "" {
+		return "", fmt.Errorf("%s message has no event key", m.Type)
+	}
+
+	return data.EventKey, nil
+}
+
+// Match returns the match of a match_score message.
+func (m WebhookMessage) Match() (store.Match, error) {
+	var data struct {
+		EventKey string `json:"event_key"`
+		Match    match  `json:"match"`
+	}
+	if err := json.Unmarshal(m.Data, &data); err != nil {
+		return store.Match{}, fmt.Errorf("unable to decode %s message data: %w", m.Type, err)
+	}
+
+	if data.EventKey == "" {
+		return store.Match{}, fmt.Errorf("%s message has no event key", m.Type)
+	}
+
+	return storeMatch(data.EventKey, data.Match)
+}
+
+// VerificationKey returns the key of a verification message, which must be entered on TBA
+// to start receiving messages.
+func (m WebhookMessage) VerificationKey() (string, error) {
+	var data struct {
+		VerificationKey string `json:"verification_key"`
+	}
+	if err := json.Unmarshal(m.Data, &data); err != nil {
+		return "", fmt.Errorf("unable to decode %s message data: %w", m.Type, err)
+	}
+
+	return data.VerificationKey, nil
+}
@@ -0,0 +1,134 @@
+package tba
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/npmanos/4176Gameday-backend/internal/store"
+	"github.com/google/go-cmp/cmp"
+	"github.com/lib/pq"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	const secret = "notARealSecret"
+	body := []byte(`{"message_type": "ping", "message_data": {}}`)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	testCases := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		expected  bool
+	}{
+		{name: "valid", secret: secret, body: body, signature: signature, expected: true},
+		{name: "wrong secret", secret: "otherSecret", body: body, signature: signature},
+		{name: "modified body", secret: secret, body: []byte(`{"message_type": "ping"}`), signature: signature},
+		{name: "missing signature", secret: secret, body: body},
+		{name: "invalid hex", secret: secret, body: body, signature: "zz"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if valid := VerifyWebhook(tt.secret, tt.body, tt.signature); valid != tt.expected {
+				t.Errorf("expected signature to be valid: %t, but got %t", tt.expected, valid)
+			}
+		})
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	t.Run("match score", func(t *testing.T) {
+		m, err := ParseWebhook([]byte(`{
+			"message_type": "match_score",
+			"message_data": {
+				"event_name": "Rocket City Regional",
+				"event_key": "2018alhu",
+				"match_key": "2018alhu_qm1",
+				"match": {
+					"key": "2018alhu_qm1",
+					"actual_time": 1520090745,
+					"alliances": {
+						"red": {"score": 220, "team_keys": ["frc254", "frc1234", "frc00"]},
+						"blue": {"score": -1, "team_keys": ["frc2733", "frc9876", "frc1"]}
+					},
+					"score_breakdown": {
+						"red": {"foobar": 3},
+						"blue": {"foobar": 1}
+					}
+				}
+			}
+		}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if m.Type != WebhookMatchScore {
+			t.Errorf("expected type %s, but got %s", WebhookMatchScore, m.Type)
+		}
+
+		eventKey, err := m.EventKey()
+		if err != nil || eventKey != "2018alhu" {
+			t.Errorf("expected event key 2018alhu, but got %q and error: %v", eventKey, err)
+		}
+
+		match, err := m.Match()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := store.Match{
+			Key:                "qm1",
+			EventKey:           "2018alhu",
+			ActualTime:         newTime(time.Unix(1520090745, 0)),
+			RedScore:           newInt(220),
+			RedAlliance:        pq.StringArray{"frc254", "frc1234", "frc00"},
+			BlueAlliance:       pq.StringArray{"frc2733", "frc9876", "frc1"},
+			RedScoreBreakdown:  store.ScoreBreakdown{"foobar": 3.0},
+			BlueScoreBreakdown: store.ScoreBreakdown{"foobar": 1.0},
+			TBAURL:             newString("https://www.thebluealliance.com/match/2018alhu_qm1"),
+			Videos:             []string{},
+		}
+
+		if !cmp.Equal(expected, match) {
+			t.Errorf("expected match to equal expected, but got diff: %s", cmp.Diff(expected, match))
+		}
+	})
+
+	t.Run("verification", func(t *testing.T) {
+		m, err := ParseWebhook([]byte(`{"message_type": "verification", "message_data": {"verification_key": "abc123"}}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		key, err := m.VerificationKey()
+		if err != nil || key != "abc123" {
+			t.Errorf("expected verification key abc123, but got %q and error: %v", key, err)
+		}
+	})
+
+	t.Run("no event key", func(t *testing.T) {
+		m, err := ParseWebhook([]byte(`{"message_type": "schedule_updated", "message_data": {"event_name": "Rocket City Regional"}}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := m.EventKey(); err == nil {
+			t.Errorf("expected error for message with no event key")
+		}
+	})
+
+	for name, body := range map[string]string{"invalid JSON": `{"message_type":`, "no type": `{"message_data": {}}`} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseWebhook([]byte(body)); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE pick_lists;
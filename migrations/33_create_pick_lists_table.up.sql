@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS pick_lists (
+    id SERIAL PRIMARY KEY,
+    event_key TEXT NOT NULL REFERENCES events ON DELETE CASCADE,
+    realm_id INTEGER NOT NULL REFERENCES realms ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    teams JSONB NOT NULL DEFAULT '[]',
+
+    UNIQUE(event_key, realm_id, name)
+);
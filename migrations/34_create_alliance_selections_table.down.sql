@@ -0,0 +1 @@
+DROP TABLE alliance_selections;
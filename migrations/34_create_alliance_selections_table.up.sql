@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS alliance_selections (
+    event_key TEXT PRIMARY KEY REFERENCES events ON DELETE CASCADE,
+    alliances JSONB NOT NULL DEFAULT '[]',
+    declined TEXT[] NOT NULL DEFAULT '{}',
+    from_tba BOOLEAN NOT NULL DEFAULT false
+);
@@ -0,0 +1 @@
+DROP TABLE points_stats;
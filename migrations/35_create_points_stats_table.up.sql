@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS points_stats (
+    realm_id INTEGER NOT NULL REFERENCES realms ON DELETE CASCADE,
+    schema_id INTEGER NOT NULL REFERENCES schemas ON DELETE CASCADE,
+    name TEXT NOT NULL,
+
+    PRIMARY KEY(realm_id, schema_id)
+);
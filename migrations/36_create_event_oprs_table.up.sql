@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS event_oprs (
+    event_key TEXT NOT NULL REFERENCES events ON DELETE CASCADE,
+    team_key TEXT NOT NULL,
+    opr DOUBLE PRECISION NOT NULL,
+    dpr DOUBLE PRECISION NOT NULL,
+    ccwm DOUBLE PRECISION NOT NULL,
+    components JSONB NOT NULL DEFAULT '{}',
+
+    PRIMARY KEY(event_key, team_key)
+);
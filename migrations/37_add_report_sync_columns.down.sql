@@ -0,0 +1,7 @@
+BEGIN;
+DROP INDEX reports_event_key_revision_idx;
+ALTER TABLE reports DROP COLUMN revision;
+ALTER TABLE reports DROP COLUMN updated_at;
+ALTER TABLE reports DROP COLUMN client_id;
+DROP SEQUENCE reports_revision_seq;
+COMMIT;
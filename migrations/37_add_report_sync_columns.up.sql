@@ -0,0 +1,7 @@
+BEGIN;
+CREATE SEQUENCE reports_revision_seq;
+ALTER TABLE reports ADD COLUMN client_id TEXT;
+ALTER TABLE reports ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE reports ADD COLUMN revision BIGINT NOT NULL DEFAULT nextval('reports_revision_seq');
+CREATE INDEX reports_event_key_revision_idx ON reports (event_key, revision);
+COMMIT;
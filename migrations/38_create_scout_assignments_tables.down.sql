@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE scout_assignments;
+DROP TABLE scout_schedules;
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+CREATE TABLE IF NOT EXISTS scout_schedules (
+    realm_id INTEGER NOT NULL REFERENCES realms ON DELETE CASCADE,
+    event_key TEXT NOT NULL REFERENCES events ON DELETE CASCADE,
+    options JSONB NOT NULL DEFAULT '{}',
+    fingerprint TEXT NOT NULL,
+
+    PRIMARY KEY(realm_id, event_key)
+);
+
+CREATE TABLE IF NOT EXISTS scout_assignments (
+    realm_id INTEGER NOT NULL,
+    event_key TEXT NOT NULL,
+    match_key TEXT NOT NULL,
+    team_key TEXT NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users ON DELETE CASCADE,
+
+    PRIMARY KEY(realm_id, event_key, match_key, team_key),
+    FOREIGN KEY(realm_id, event_key) REFERENCES scout_schedules ON DELETE CASCADE
+);
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+ALTER TABLE reports DROP COLUMN schema_version;
+ALTER TABLE schemas DROP COLUMN migrations;
+ALTER TABLE schemas DROP COLUMN version;
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+ALTER TABLE schemas ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE schemas ADD COLUMN migrations JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE reports ADD COLUMN schema_version INTEGER NOT NULL DEFAULT 1;
+COMMIT;
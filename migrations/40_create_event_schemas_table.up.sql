@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS event_schemas (
+    realm_id INTEGER NOT NULL REFERENCES realms ON DELETE CASCADE,
+    event_key TEXT NOT NULL REFERENCES events ON DELETE CASCADE,
+    schema_id INTEGER NOT NULL REFERENCES schemas ON DELETE CASCADE,
+
+    PRIMARY KEY(realm_id, event_key)
+);
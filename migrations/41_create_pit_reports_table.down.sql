@@ -0,0 +1,13 @@
+BEGIN;
+DROP TABLE pit_reports;
+
+DELETE FROM event_schemas WHERE kind != 'match';
+ALTER TABLE event_schemas DROP CONSTRAINT event_schemas_pkey;
+ALTER TABLE event_schemas ADD PRIMARY KEY (realm_id, event_key);
+ALTER TABLE event_schemas DROP COLUMN kind;
+
+DELETE FROM schemas WHERE kind != 'match';
+ALTER TABLE schemas DROP CONSTRAINT schemas_year_kind_key;
+ALTER TABLE schemas ADD CONSTRAINT schemas_year_key UNIQUE (year);
+ALTER TABLE schemas DROP COLUMN kind;
+COMMIT;
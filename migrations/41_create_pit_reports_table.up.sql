@@ -0,0 +1,23 @@
+BEGIN;
+ALTER TABLE schemas ADD COLUMN kind TEXT NOT NULL DEFAULT 'match';
+ALTER TABLE schemas DROP CONSTRAINT schemas_year_key;
+ALTER TABLE schemas ADD CONSTRAINT schemas_year_kind_key UNIQUE (year, kind);
+
+ALTER TABLE event_schemas ADD COLUMN kind TEXT NOT NULL DEFAULT 'match';
+ALTER TABLE event_schemas DROP CONSTRAINT event_schemas_pkey;
+ALTER TABLE event_schemas ADD PRIMARY KEY (realm_id, event_key, kind);
+
+CREATE TABLE IF NOT EXISTS pit_reports (
+    id SERIAL PRIMARY KEY,
+    event_key TEXT NOT NULL,
+    team_key TEXT NOT NULL,
+    realm_id INTEGER NOT NULL REFERENCES realms ON DELETE CASCADE,
+    reporter_id INTEGER REFERENCES users ON DELETE SET NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    photos TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE(event_key, team_key, realm_id),
+    FOREIGN KEY(team_key, event_key) REFERENCES teams (key, event_key) ON DELETE CASCADE
+);
+COMMIT;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS comments (
+    id SERIAL PRIMARY KEY,
+    event_key TEXT NOT NULL,
+    match_key TEXT,
+    team_key TEXT NOT NULL,
+    reporter_id INTEGER REFERENCES users ON DELETE SET NULL,
+    realm_id INTEGER REFERENCES realms ON DELETE SET NULL,
+    comment TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    FOREIGN KEY(team_key, event_key) REFERENCES teams (key, event_key) ON DELETE CASCADE,
+    FOREIGN KEY(event_key, match_key) REFERENCES matches (event_key, key) ON DELETE CASCADE
+);
+
+CREATE INDEX comments_event_key_team_key_idx ON comments (event_key, team_key);
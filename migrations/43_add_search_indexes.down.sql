@@ -0,0 +1,6 @@
+BEGIN;
+DROP INDEX reports_comment_search_idx;
+DROP INDEX comments_comment_search_idx;
+DROP INDEX all_teams_nickname_search_idx;
+DROP INDEX events_name_search_idx;
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+CREATE INDEX reports_comment_search_idx ON reports USING GIN (to_tsvector('english', comment));
+CREATE INDEX comments_comment_search_idx ON comments USING GIN (to_tsvector('english', comment));
+CREATE INDEX all_teams_nickname_search_idx ON all_teams USING GIN (to_tsvector('english', coalesce(nickname, '')));
+CREATE INDEX events_name_search_idx ON events USING GIN (to_tsvector('english', name));
+COMMIT;
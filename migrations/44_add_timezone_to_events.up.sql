@@ -0,0 +1 @@
+ALTER TABLE events ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
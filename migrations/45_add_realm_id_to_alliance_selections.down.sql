@@ -0,0 +1,7 @@
+BEGIN;
+DELETE FROM alliance_selections WHERE realm_id IS NOT NULL;
+DROP INDEX alliance_selections_tba_event_key_idx;
+ALTER TABLE alliance_selections DROP CONSTRAINT alliance_selections_realm_id_event_key_key;
+ALTER TABLE alliance_selections DROP COLUMN realm_id;
+ALTER TABLE alliance_selections ADD PRIMARY KEY (event_key);
+COMMIT;
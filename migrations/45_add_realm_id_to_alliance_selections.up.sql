@@ -0,0 +1,6 @@
+BEGIN;
+ALTER TABLE alliance_selections ADD COLUMN realm_id INTEGER REFERENCES realms ON DELETE CASCADE;
+ALTER TABLE alliance_selections DROP CONSTRAINT alliance_selections_pkey;
+ALTER TABLE alliance_selections ADD CONSTRAINT alliance_selections_realm_id_event_key_key UNIQUE (realm_id, event_key);
+CREATE UNIQUE INDEX alliance_selections_tba_event_key_idx ON alliance_selections (event_key) WHERE realm_id IS NULL;
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+ALTER TABLE reports ADD COLUMN revision_txid BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE reports ALTER COLUMN revision_txid SET DEFAULT txid_current();
+CREATE INDEX reports_event_key_revision_txid_idx ON reports (event_key, revision_txid);
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+ALTER TABLE reports ADD COLUMN schema_id INTEGER REFERENCES schemas ON DELETE SET NULL;
+UPDATE reports SET schema_id = (
+	SELECT COALESCE(es.schema_id, events.schema_id, s.id)
+	FROM events
+	LEFT JOIN event_schemas es
+		ON es.event_key = events.key AND es.realm_id = reports.realm_id AND es.kind = 'match'
+	LEFT JOIN schemas s
+		ON s.year = EXTRACT(YEAR FROM events.start_date) AND s.kind = 'match'
+	WHERE events.key = reports.event_key
+);
+COMMIT;
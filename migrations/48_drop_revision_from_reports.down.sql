@@ -0,0 +1,11 @@
+BEGIN;
+CREATE SEQUENCE reports_revision_seq;
+ALTER TABLE reports ADD COLUMN revision BIGINT;
+UPDATE reports SET revision = ordered.revision
+    FROM (SELECT id, row_number() OVER (ORDER BY revision_txid, id) AS revision FROM reports) ordered
+    WHERE reports.id = ordered.id;
+SELECT setval('reports_revision_seq', COALESCE(MAX(revision), 0) + 1, false) FROM reports;
+ALTER TABLE reports ALTER COLUMN revision SET DEFAULT nextval('reports_revision_seq');
+ALTER TABLE reports ALTER COLUMN revision SET NOT NULL;
+CREATE INDEX reports_event_key_revision_idx ON reports (event_key, revision);
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+DROP INDEX reports_event_key_revision_idx;
+ALTER TABLE reports DROP COLUMN revision;
+DROP SEQUENCE reports_revision_seq;
+COMMIT;